@@ -0,0 +1,1104 @@
+package schyntax
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// SetOperationError is returned by Union, Intersect and Subtract when the result can't be written as a schedule, or
+// would never match any time.
+type SetOperationError struct {
+	message string
+	input   string
+	empty   bool
+}
+
+func newSetOperationError(msg, input string) *SetOperationError {
+	return &SetOperationError{message: msg, input: input}
+}
+
+func (e *SetOperationError) Error() string {
+	return e.message
+}
+
+// Input returns the text of the schedule on the left side of the operation.
+func (e *SetOperationError) Input() string {
+	return e.input
+}
+
+func (e *SetOperationError) Index() int {
+	return 0
+}
+
+// Empty returns true if the operation failed because the result would never match any time, rather than because the
+// result can't be written in schyntax.
+func (e *SetOperationError) Empty() bool {
+	return e.empty
+}
+
+// Union returns a schedule which matches every time that either a or b matches. Both schedules must be evaluated in the
+// same location. If both schedules use a calendar with the same name, the calendar of a is used. The result's text is
+// made of the groups of both schedules which can match any time, written out in full.
+//
+// The result of each operation counts business days with the weekend and holidays of a, unless only b has businessdays
+// expressions.
+func Union(a, b Schedule) (Schedule, error) {
+	return combine(a, b, func(left, right *scheduleImpl) []*internals.IrGroup {
+		var groups []*internals.IrGroup
+		for _, g := range append(append([]*internals.IrGroup(nil), left.ir.Groups...), right.ir.Groups...) {
+			if left.isSatisfiable(g) {
+				groups = append(groups, g)
+			}
+		}
+
+		return groups
+	})
+}
+
+// Intersect returns a schedule which matches every time that both a and b match. Each group of a is intersected with
+// each group of b. Both schedules must be evaluated in the same location.
+//
+// Two groups which restrict the same day level unit (days of the month, days of the year or dates) can only be
+// intersected when their ranges count from the same end of the month or year, or when one of them only uses dates
+// with years. Two groups which both include calendars can only be intersected when they include the same ones, and the
+// same goes for business days. Otherwise, a *SetOperationError is returned.
+func Intersect(a, b Schedule) (Schedule, error) {
+	return combine(a, b, func(left, right *scheduleImpl) []*internals.IrGroup {
+		var groups []*internals.IrGroup
+		for _, x := range left.ir.Groups {
+			for _, y := range right.ir.Groups {
+				if g := intersectGroups(x, y); g != nil && left.isSatisfiable(g) {
+					groups = append(groups, g)
+				}
+			}
+		}
+
+		return groups
+	})
+}
+
+// Subtract returns a schedule which matches every time that a matches, but b doesn't. Where possible, the ranges of b
+// become exclusions in the groups of a. Otherwise, a group of a is split into one group for each unit that b
+// restricts. Both schedules must be evaluated in the same location.
+//
+// Subtract returns a *SetOperationError under the same conditions as Intersect, and when b has every or businessdays
+// expressions.
+func Subtract(a, b Schedule) (Schedule, error) {
+	return combine(a, b, func(left, right *scheduleImpl) []*internals.IrGroup {
+		groups := left.ir.Groups
+		for _, y := range right.ir.Groups {
+			var remaining []*internals.IrGroup
+			for _, x := range groups {
+				for _, g := range subtractGroup(x, y) {
+					if left.isSatisfiable(g) {
+						remaining = append(remaining, g)
+					}
+				}
+			}
+
+			groups = remaining
+		}
+
+		return groups
+	})
+}
+
+// combine checks that two schedules can be combined, and creates a schedule from the groups returned by operation.
+func combine(a, b Schedule, operation func(left, right *scheduleImpl) []*internals.IrGroup) (sch Schedule, err error) {
+	left, err := scheduleImplOf(a)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := scheduleImplOf(b)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		// errors raised while combining groups don't know which schedule they came from
+		if e, ok := err.(*SetOperationError); ok && e.input == "" {
+			e.input = left.originalText
+		}
+	}()
+
+	defer recoverError(left.originalText, &err)
+
+	if left.loc.String() != right.loc.String() {
+		return nil, newSetOperationError("Schedules in different locations ("+left.loc.String()+" and "+right.loc.String()+") can't be combined.", left.originalText)
+	}
+
+	for _, s := range []*scheduleImpl{left, right} {
+		if s.hasJitter() {
+			return nil, newSetOperationError("Schedules with jitter can't be combined.", s.originalText)
+		}
+	}
+
+	// the result refers to the calendars of both schedules, and a calendar of a takes precedence over one of b with
+	// the same name
+	calendars := make(map[string]Calendar)
+	for _, s := range []*scheduleImpl{right, left} {
+		for name, calendar := range s.calendars {
+			calendars[name] = calendar
+		}
+	}
+
+	merged := *left
+	merged.calendars = calendars
+	if !left.usesBusinessDays() && right.usesBusinessDays() {
+		merged.weekend = right.weekend
+		merged.holidays = right.holidays
+	}
+
+	groups := operation(&merged, right)
+	if len(groups) == 0 {
+		e := newSetOperationError("The result of the operation never matches any time.", left.originalText)
+		e.empty = true
+		return nil, e
+	}
+
+	options := []Option{InLocation(left.loc), SearchHorizon(max(left.horizon, right.horizon)), withBusinessWeek(merged.weekend, merged.holidays)}
+	for name, calendar := range calendars {
+		options = append(options, WithCalendar(name, calendar))
+	}
+
+	return New(irProgramText(groups), options...)
+}
+
+// scheduleImplOf returns the implementation of a schedule, recreating it from its text if it was implemented elsewhere.
+func scheduleImplOf(sch Schedule) (*scheduleImpl, error) {
+	if impl, ok := sch.(*scheduleImpl); ok {
+		return impl, nil
+	}
+
+	created, err := New(sch.OriginalText(), InLocation(sch.Location()))
+	if err != nil {
+		return nil, err
+	}
+
+	return created.(*scheduleImpl), nil
+}
+
+/**********************************************************************************************
+ * Intersection and difference of groups
+**********************************************************************************************/
+
+// intersectGroups returns a group which matches the times matched by both a and b, or nil if no time can match both.
+func intersectGroups(a, b *internals.IrGroup) *internals.IrGroup {
+	g := internals.NewIrGroup()
+	empty := false
+
+	setMask := func(ranges *[]*internals.IrIntegerRange, mask uint64, min, max int) {
+		*ranges = maskRanges(mask, min, max)
+		empty = empty || mask == 0
+	}
+
+	milliseconds := a.MillisecondsMask.And(b.MillisecondsMask)
+	g.Milliseconds = valueRanges(milliseconds.Values(), 0, 999)
+	empty = milliseconds.IsEmpty()
+
+	setMask(&g.Seconds, a.SecondsMask&b.SecondsMask, 0, 59)
+	setMask(&g.Minutes, a.MinutesMask&b.MinutesMask, 0, 59)
+	setMask(&g.Hours, uint64(a.HoursMask&b.HoursMask), 0, 23)
+	if a.HasMonths() || a.HasMonthsExcluded() || b.HasMonths() || b.HasMonthsExcluded() {
+		setMask(&g.Months, uint64(a.MonthsMask&b.MonthsMask), 1, 12)
+	}
+
+	var e bool
+	g.DaysOfWeek, e = intersectDaysOfWeek(a, b)
+	empty = empty || e
+	g.DaysOfMonth, e = intersectDayRanges(a.DaysOfMonth, b.DaysOfMonth, 31)
+	empty = empty || e
+	g.DaysOfYear, e = intersectDayRanges(a.DaysOfYear, b.DaysOfYear, 366)
+	empty = empty || e
+	g.Dates, e = intersectDateRanges(a.Dates, b.Dates)
+	empty = empty || e
+	g.Weeks, e = intersectDayRanges(a.Weeks, b.Weeks, 53)
+	empty = empty || e
+	g.Years, e = intersectYears(a.Years, b.Years)
+	empty = empty || e
+	g.Periods = intersectPeriods(a.Periods, b.Periods)
+	g.BusinessDays = intersectBusinessDays(a.BusinessDays, b.BusinessDays)
+	g.Calendars = intersectCalendars(a.Calendars, b.Calendars)
+
+	if empty {
+		return nil
+	}
+
+	// a time which is excluded by either group is excluded from the intersection
+	g.DaysOfWeekExcluded = concat(a.DaysOfWeekExcluded, b.DaysOfWeekExcluded)
+	g.DaysOfMonthExcluded = concat(a.DaysOfMonthExcluded, b.DaysOfMonthExcluded)
+	g.DaysOfYearExcluded = concat(a.DaysOfYearExcluded, b.DaysOfYearExcluded)
+	g.DatesExcluded = concat(a.DatesExcluded, b.DatesExcluded)
+	g.WeeksExcluded = concat(a.WeeksExcluded, b.WeeksExcluded)
+	g.BusinessDaysExcluded = concat(a.BusinessDaysExcluded, b.BusinessDaysExcluded)
+	g.YearsExcluded = concat(a.YearsExcluded, b.YearsExcluded)
+	g.CalendarsExcluded = concat(a.CalendarsExcluded, b.CalendarsExcluded)
+
+	g.CompileMasks()
+	return g
+}
+
+// subtractGroup returns groups which together match the times matched by a, but not b. A time isn't matched by b if any
+// one of b's units rejects it, so there is one group for each way that b can reject a time which a matches.
+func subtractGroup(a, b *internals.IrGroup) []*internals.IrGroup {
+	if a.MillisecondsMask.And(b.MillisecondsMask).IsEmpty() || a.SecondsMask&b.SecondsMask == 0 || a.MinutesMask&b.MinutesMask == 0 ||
+		a.HoursMask&b.HoursMask == 0 || a.MonthsMask&b.MonthsMask == 0 {
+		// the groups never match the same time, so there's nothing to subtract
+		return []*internals.IrGroup{a}
+	}
+
+	var groups []*internals.IrGroup
+	add := func(modify func(g *internals.IrGroup) bool) {
+		g := cloneGroup(a)
+		if modify(g) {
+			g.CompileMasks()
+			groups = append(groups, g)
+		}
+	}
+
+	subtractMask := func(ranges, excluded *[]*internals.IrIntegerRange, mask uint64, min, max int) bool {
+		*ranges = maskRanges(mask, min, max)
+		*excluded = nil
+		return mask != 0
+	}
+
+	if mask := a.MillisecondsMask.AndNot(b.MillisecondsMask); !mask.IsEmpty() {
+		add(func(g *internals.IrGroup) bool {
+			g.Milliseconds = valueRanges(mask.Values(), 0, 999)
+			g.MillisecondsExcluded = nil
+			return true
+		})
+	}
+
+	if mask := a.SecondsMask &^ b.SecondsMask; mask != 0 {
+		add(func(g *internals.IrGroup) bool { return subtractMask(&g.Seconds, &g.SecondsExcluded, mask, 0, 59) })
+	}
+
+	if mask := a.MinutesMask &^ b.MinutesMask; mask != 0 {
+		add(func(g *internals.IrGroup) bool { return subtractMask(&g.Minutes, &g.MinutesExcluded, mask, 0, 59) })
+	}
+
+	if mask := uint64(a.HoursMask &^ b.HoursMask); mask != 0 {
+		add(func(g *internals.IrGroup) bool { return subtractMask(&g.Hours, &g.HoursExcluded, mask, 0, 23) })
+	}
+
+	if mask := uint64(a.MonthsMask &^ b.MonthsMask); mask != 0 {
+		add(func(g *internals.IrGroup) bool { return subtractMask(&g.Months, &g.MonthsExcluded, mask, 1, 12) })
+	}
+
+	// For the day level units, a time is rejected by b if it's outside of b's ranges, which become exclusions, or if
+	// it's inside one of b's exclusions, which are intersected with a's ranges.
+	if b.HasDaysOfWeek() {
+		add(func(g *internals.IrGroup) bool {
+			g.DaysOfWeekExcluded = concat(g.DaysOfWeekExcluded, b.DaysOfWeek)
+			return true
+		})
+	}
+
+	if b.HasDaysOfWeekExcluded() {
+		add(func(g *internals.IrGroup) bool {
+			excluded := internals.NewIrGroup()
+			excluded.DaysOfWeek = b.DaysOfWeekExcluded
+			excluded.CompileMasks()
+
+			var empty bool
+			g.DaysOfWeek, empty = intersectDaysOfWeek(a, excluded)
+			return !empty
+		})
+	}
+
+	if b.HasDaysOfMonth() {
+		add(func(g *internals.IrGroup) bool {
+			g.DaysOfMonthExcluded = concat(g.DaysOfMonthExcluded, b.DaysOfMonth)
+			return true
+		})
+	}
+
+	if b.HasDaysOfMonthExcluded() {
+		add(func(g *internals.IrGroup) bool {
+			var empty bool
+			g.DaysOfMonth, empty = intersectDayRanges(a.DaysOfMonth, b.DaysOfMonthExcluded, 31)
+			return !empty
+		})
+	}
+
+	if b.HasDaysOfYear() {
+		add(func(g *internals.IrGroup) bool {
+			g.DaysOfYearExcluded = concat(g.DaysOfYearExcluded, b.DaysOfYear)
+			return true
+		})
+	}
+
+	if b.HasDaysOfYearExcluded() {
+		add(func(g *internals.IrGroup) bool {
+			var empty bool
+			g.DaysOfYear, empty = intersectDayRanges(a.DaysOfYear, b.DaysOfYearExcluded, 366)
+			return !empty
+		})
+	}
+
+	if b.HasDates() {
+		add(func(g *internals.IrGroup) bool {
+			g.DatesExcluded = concat(g.DatesExcluded, b.Dates)
+			return true
+		})
+	}
+
+	if b.HasDatesExcluded() {
+		add(func(g *internals.IrGroup) bool {
+			var empty bool
+			g.Dates, empty = intersectDateRanges(a.Dates, b.DatesExcluded)
+			return !empty
+		})
+	}
+
+	if b.HasWeeks() {
+		add(func(g *internals.IrGroup) bool {
+			g.WeeksExcluded = concat(g.WeeksExcluded, b.Weeks)
+			return true
+		})
+	}
+
+	if b.HasWeeksExcluded() {
+		add(func(g *internals.IrGroup) bool {
+			var empty bool
+			g.Weeks, empty = intersectDayRanges(a.Weeks, b.WeeksExcluded, 53)
+			return !empty
+		})
+	}
+
+	if b.HasYears() {
+		add(func(g *internals.IrGroup) bool {
+			g.YearsExcluded = concat(g.YearsExcluded, b.Years)
+			return true
+		})
+	}
+
+	if b.HasYearsExcluded() {
+		add(func(g *internals.IrGroup) bool {
+			var empty bool
+			g.Years, empty = intersectYears(a.Years, b.YearsExcluded)
+			return !empty
+		})
+	}
+
+	if b.HasPeriods() {
+		panic(newSetOperationError("Schedules with every expressions can't be subtracted.", ""))
+	}
+
+	// a group with a businessdays expression only matches business days, so excluding its business days from a would
+	// also exclude every other day
+	if b.HasBusinessDays() || b.HasBusinessDaysExcluded() {
+		panic(newSetOperationError("Schedules with businessdays expressions can't be subtracted.", ""))
+	}
+
+	if b.HasCalendars() {
+		add(func(g *internals.IrGroup) bool {
+			g.CalendarsExcluded = concat(g.CalendarsExcluded, b.Calendars)
+			return true
+		})
+	}
+
+	if b.HasCalendarsExcluded() {
+		add(func(g *internals.IrGroup) bool {
+			g.Calendars = intersectCalendars(a.Calendars, b.CalendarsExcluded)
+			return true
+		})
+	}
+
+	return groups
+}
+
+func cloneGroup(group *internals.IrGroup) *internals.IrGroup {
+	g := *group
+	g.Milliseconds = concat(group.Milliseconds)
+	g.MillisecondsExcluded = concat(group.MillisecondsExcluded)
+	g.Seconds = concat(group.Seconds)
+	g.SecondsExcluded = concat(group.SecondsExcluded)
+	g.Minutes = concat(group.Minutes)
+	g.MinutesExcluded = concat(group.MinutesExcluded)
+	g.Hours = concat(group.Hours)
+	g.HoursExcluded = concat(group.HoursExcluded)
+	g.DaysOfWeek = concat(group.DaysOfWeek)
+	g.DaysOfWeekExcluded = concat(group.DaysOfWeekExcluded)
+	g.DaysOfMonth = concat(group.DaysOfMonth)
+	g.DaysOfMonthExcluded = concat(group.DaysOfMonthExcluded)
+	g.DaysOfYear = concat(group.DaysOfYear)
+	g.DaysOfYearExcluded = concat(group.DaysOfYearExcluded)
+	g.Months = concat(group.Months)
+	g.MonthsExcluded = concat(group.MonthsExcluded)
+	g.Dates = concat(group.Dates)
+	g.DatesExcluded = concat(group.DatesExcluded)
+	g.Weeks = concat(group.Weeks)
+	g.WeeksExcluded = concat(group.WeeksExcluded)
+	g.Years = concat(group.Years)
+	g.YearsExcluded = concat(group.YearsExcluded)
+	g.Periods = concat(group.Periods)
+	g.BusinessDays = concat(group.BusinessDays)
+	g.BusinessDaysExcluded = concat(group.BusinessDaysExcluded)
+	g.Calendars = concat(group.Calendars)
+	g.CalendarsExcluded = concat(group.CalendarsExcluded)
+	return &g
+}
+
+// concat returns a new slice containing the elements of each slice, so that modifying the result never affects them.
+func concat[T any](slices ...[]T) []T {
+	var result []T
+	for _, s := range slices {
+		result = append(result, s...)
+	}
+
+	return result
+}
+
+// intersectDaysOfWeek returns the days of week ranges which match the days matched by both groups' ranges (ignoring
+// their exclusions), and whether no day can match.
+func intersectDaysOfWeek(a, b *internals.IrGroup) (ranges []*internals.IrIntegerRange, empty bool) {
+	if !a.HasDaysOfWeek() {
+		return concat(b.DaysOfWeek), false
+	}
+
+	if !b.HasDaysOfWeek() {
+		return concat(a.DaysOfWeek), false
+	}
+
+	// the masks only include days which don't use the # operator
+	ranges = maskRanges(uint64(a.DaysOfWeekMask&b.DaysOfWeekMask), 1, 7)
+
+	keepNth := func(r *internals.IrIntegerRange, other *internals.IrGroup) {
+		if other.DaysOfWeekMask&(1<<uint(r.Start)) != 0 {
+			ranges = append(ranges, r)
+			return
+		}
+
+		for _, o := range other.DaysOfWeek {
+			if !o.HasNth || o.Start != r.Start {
+				continue
+			}
+
+			if o.Nth == r.Nth {
+				ranges = append(ranges, r)
+				return
+			}
+
+			if (o.Nth > 0) != (r.Nth > 0) {
+				panic(newSetOperationError("The # operator can't be combined when one occurrence counts from the start of the month and the other counts from the end.", ""))
+			}
+		}
+	}
+
+	for _, r := range a.DaysOfWeek {
+		if r.HasNth {
+			keepNth(r, b)
+		}
+	}
+
+	for _, r := range b.DaysOfWeek {
+		// occurrences which are also in a have already been kept
+		if r.HasNth && a.DaysOfWeekMask&(1<<uint(r.Start)) != 0 {
+			ranges = append(ranges, r)
+		}
+	}
+
+	return ranges, len(ranges) == 0
+}
+
+// intersectYears returns years ranges which match the years matched by both a and b, and whether no year can match.
+func intersectYears(a, b []*internals.IrIntegerRange) (ranges []*internals.IrIntegerRange, empty bool) {
+	if len(a) == 0 {
+		return concat(b), false
+	}
+
+	if len(b) == 0 {
+		return concat(a), false
+	}
+
+	var values []int
+	for year := 1900; year <= 2200; year++ {
+		if rangesContain(a, year) && rangesContain(b, year) {
+			values = append(values, year)
+		}
+	}
+
+	return valueRanges(values, 1900, 2200), len(values) == 0
+}
+
+// rangesContain returns true if any of the ranges contains value. The ranges can't be split.
+func rangesContain(ranges []*internals.IrIntegerRange, value int) bool {
+	for _, r := range ranges {
+		if r.Contains(value, 0) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// intersectPeriods returns the periods which match the dates in both a and b. Like calendars, the periods of a group
+// match any date which is in one of them, so the intersection can only be written when one side doesn't have any, or
+// both sides are the same.
+func intersectPeriods(a, b []*internals.IrPeriod) []*internals.IrPeriod {
+	if len(a) == 0 {
+		return concat(b)
+	}
+
+	if len(b) == 0 || strings.Join(irPeriodsText(a), " ") == strings.Join(irPeriodsText(b), " ") {
+		return concat(a)
+	}
+
+	panic(newSetOperationError("Groups which both have every expressions can't be intersected unless they have the same ones.", ""))
+}
+
+// intersectBusinessDays returns the business days which match the dates in both a and b. Business days which count from
+// different places can't be combined into one range, so like calendars, the intersection can only be written when one
+// side is unrestricted, or both sides are the same.
+func intersectBusinessDays(a, b []*internals.IrIntegerRange) []*internals.IrIntegerRange {
+	if len(a) == 0 {
+		return concat(b)
+	}
+
+	if len(b) == 0 || strings.Join(irBusinessDaysText(a, nil), " ") == strings.Join(irBusinessDaysText(b, nil), " ") {
+		return concat(a)
+	}
+
+	panic(newSetOperationError("Groups which both have businessdays expressions can't be intersected unless they have the same ones.", ""))
+}
+
+// intersectCalendars returns the calendars which match the dates in both a and b. A group matches a date in any of its
+// calendars, so unless one side is unrestricted, the intersection can only be written when both sides are the same.
+func intersectCalendars(a, b []string) []string {
+	if len(a) == 0 {
+		return concat(b)
+	}
+
+	if len(b) == 0 || strings.Join(a, ",") == strings.Join(b, ",") {
+		return concat(a)
+	}
+
+	panic(newSetOperationError("Groups which both include calendars can't be intersected unless they include the same calendars.", ""))
+}
+
+// intersectDayRanges returns days of the month or year (or weeks of the year) ranges which match the days matched by both
+// a and b, and whether no day can match. max is the largest value of the unit.
+func intersectDayRanges(a, b []*internals.IrIntegerRange, max int) (ranges []*internals.IrIntegerRange, empty bool) {
+	if len(a) == 0 {
+		return concat(b), false
+	}
+
+	if len(b) == 0 {
+		return concat(a), false
+	}
+
+	sign := dayRangesSign(a)
+	if sign == 0 || sign != dayRangesSign(b) {
+		panic(newSetOperationError("Days or weeks which count from the start of the month or year can't be intersected with ones which count from the end.", ""))
+	}
+
+	// Every range counts from the same end of the unit, and none depend on its length, so the days can be compared as
+	// if every month or year was max days long.
+	var values []int
+	for day := 1; day <= max; day++ {
+		if dayRangesContain(a, day, max) && dayRangesContain(b, day, max) {
+			values = append(values, day)
+		}
+	}
+
+	if sign < 0 {
+		for i := range values {
+			values[i] -= max + 1
+		}
+
+		return valueRanges(values, -max, -1), len(values) == 0
+	}
+
+	return valueRanges(values, 1, max), len(values) == 0
+}
+
+// dayRangesSign returns 1 if every range is made of positive days, or -1 if every range is made of negative days. It
+// returns 0 if there is a mix, or if a range's days depend on the length of the previous month or year, which is only
+// the case for split ranges with intervals.
+func dayRangesSign(ranges []*internals.IrIntegerRange) int {
+	sign := 0
+	for _, r := range ranges {
+		s := 1
+		if r.Start < 0 {
+			s = -1
+		}
+
+		if (r.IsRange && (r.End < 0) != (s < 0)) || (r.IsSplit && r.HasInterval) || (sign != 0 && s != sign) {
+			return 0
+		}
+
+		sign = s
+	}
+
+	return sign
+}
+
+// dayRangesContain returns true if any of the ranges contains day, assuming the month or year is max days long.
+func dayRangesContain(ranges []*internals.IrIntegerRange, day, max int) bool {
+	for _, r := range ranges {
+		if r.Start < 0 {
+			end := r.End
+			if r.IsRange {
+				end = max + r.End + 1
+			}
+
+			r = r.CloneWithRevisedRange(max+r.Start+1, end)
+		}
+
+		if r.Contains(day, max) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// intersectDateRanges returns date ranges which match the dates matched by both a and b, and whether no date can match.
+func intersectDateRanges(a, b []*internals.IrDateRange) (ranges []*internals.IrDateRange, empty bool) {
+	if len(a) == 0 {
+		return concat(b), false
+	}
+
+	if len(b) == 0 {
+		return concat(a), false
+	}
+
+	contains := func(ranges []*internals.IrDateRange, date time.Time) bool {
+		for _, r := range ranges {
+			if inDateRange(r, date.Year(), int(date.Month()), date.Day()) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// dates with and without years are intersected separately
+	for _, pair := range [][2][]*internals.IrDateRange{{a, b}, {b, a}} {
+		if withYears, withoutYears := splitDateRanges(pair[0]); len(withYears) > 0 && len(withoutYears) > 0 {
+			ranges, empty = intersectDateRanges(withYears, pair[1])
+			more, moreEmpty := intersectDateRanges(withoutYears, pair[1])
+			return append(ranges, more...), empty && moreEmpty
+		}
+	}
+
+	// When either side only has dates with years, the intersection is limited to those years.
+	var first, last time.Time
+	hasYears := true
+	switch {
+	case datesHaveYears(a):
+		first, last = dateRangesSpan(a)
+	case datesHaveYears(b):
+		first, last = dateRangesSpan(b)
+	case !datesHaveIntervals(a) && !datesHaveIntervals(b):
+		// Without intervals, dates without years match the same days every year. A leap year includes February 29.
+		first = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		last = time.Date(2000, 12, 31, 0, 0, 0, 0, time.UTC)
+		hasYears = false
+	default:
+		panic(newSetOperationError("Dates without years can't be intersected when they use intervals.", ""))
+	}
+
+	var previous time.Time
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		if !contains(a, date) || !contains(b, date) {
+			continue
+		}
+
+		// extend the previous range if this date immediately follows it
+		if n := len(ranges); n > 0 && previous.AddDate(0, 0, 1).Equal(date) {
+			ranges[n-1] = internals.NewIrDateRange(ranges[n-1].Start, irDate(date, hasYears), 0, false, false)
+		} else {
+			ranges = append(ranges, internals.NewIrDateRange(irDate(date, hasYears), nil, 0, false, false))
+		}
+
+		previous = date
+	}
+
+	return ranges, len(ranges) == 0
+}
+
+func irDate(date time.Time, hasYear bool) *internals.IrDate {
+	return internals.NewIrDate(date.Year(), int(date.Month()), date.Day(), hasYear)
+}
+
+// splitDateRanges separates ranges with years from ranges without.
+func splitDateRanges(ranges []*internals.IrDateRange) (withYears, withoutYears []*internals.IrDateRange) {
+	for _, r := range ranges {
+		if r.DatesHaveYear {
+			withYears = append(withYears, r)
+		} else {
+			withoutYears = append(withoutYears, r)
+		}
+	}
+
+	return withYears, withoutYears
+}
+
+func datesHaveYears(ranges []*internals.IrDateRange) bool {
+	for _, r := range ranges {
+		if !r.DatesHaveYear {
+			return false
+		}
+	}
+
+	return true
+}
+
+func datesHaveIntervals(ranges []*internals.IrDateRange) bool {
+	for _, r := range ranges {
+		if r.HasInterval {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dateRangesSpan returns the first and last dates of ranges which all have years.
+func dateRangesSpan(ranges []*internals.IrDateRange) (first, last time.Time) {
+	for i, r := range ranges {
+		end := r.Start
+		if r.IsRange {
+			end = r.End
+		}
+
+		start := time.Date(r.Start.Year, time.Month(r.Start.Month), r.Start.Day, 0, 0, 0, 0, time.UTC)
+		finish := time.Date(end.Year, time.Month(end.Month), end.Day, 0, 0, 0, 0, time.UTC)
+		if i == 0 || start.Before(first) {
+			first = start
+		}
+
+		if i == 0 || finish.After(last) {
+			last = finish
+		}
+	}
+
+	return first, last
+}
+
+// maskRanges returns ranges which match exactly the values in mask between min and max.
+func maskRanges(mask uint64, min, max int) []*internals.IrIntegerRange {
+	var values []int
+	for v := min; v <= max; v++ {
+		if mask&(1<<uint(v)) != 0 {
+			values = append(values, v)
+		}
+	}
+
+	return valueRanges(values, min, max)
+}
+
+// valueRanges returns ranges which match exactly the values, which must be in ascending order and between min and max.
+// Values which form an arithmetic progression become a single range with an interval, and consecutive values become
+// ranges.
+func valueRanges(values []int, min, max int) []*internals.IrIntegerRange {
+	if len(values) >= 3 {
+		step := values[1] - values[0]
+		progression := step > 1
+		for i := 2; i < len(values) && progression; i++ {
+			progression = values[i]-values[i-1] == step
+		}
+
+		if progression {
+			last := values[len(values)-1]
+			if values[0] == min && last+step > max {
+				last = max
+			}
+
+			return []*internals.IrIntegerRange{internals.NewIrIntegerRange(values[0], last, true, step, false, false)}
+		}
+	}
+
+	var ranges []*internals.IrIntegerRange
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+
+		ranges = append(ranges, internals.NewIrIntegerRange(values[i], values[j], j > i, 0, false, false))
+		i = j + 1
+	}
+
+	return ranges
+}
+
+/**********************************************************************************************
+ * Rendering
+**********************************************************************************************/
+
+// irProgramText returns schedule text which compiles to groups. A single group is written without curly braces.
+func irProgramText(groups []*internals.IrGroup) string {
+	var texts []string
+	seen := make(map[string]bool)
+	for _, g := range groups {
+		text := irGroupText(g)
+		if !seen[text] {
+			seen[text] = true
+			texts = append(texts, text)
+		}
+	}
+
+	if len(texts) > 1 {
+		for i := range texts {
+			texts[i] = "{" + texts[i] + "}"
+		}
+	}
+
+	return strings.Join(texts, ", ")
+}
+
+// irGroupText returns the text of a group. Milliseconds, seconds, minutes and hours are written from the group's masks,
+// and are only left out when the implied rules would produce the same values. A group whose milliseconds, seconds,
+// minutes, hours or months match no values can't be written, since leaving the expression out would match every value.
+func irGroupText(g *internals.IrGroup) string {
+	if g.MillisecondsMask.IsEmpty() || g.SecondsMask == 0 || g.MinutesMask == 0 || g.HoursMask == 0 || g.MonthsMask == 0 {
+		panic(newSetOperationError("A group which never matches any time can't be written.", ""))
+	}
+
+	var expressions []string
+	write := func(expType internals.ExpressionType, args []string) {
+		if len(args) > 0 {
+			expressions = append(expressions, canonicalExpressionNames[expType]+"("+strings.Join(args, ", ")+")")
+		}
+	}
+
+	var days []string
+	writeDays := func(expType internals.ExpressionType, args []string) {
+		if len(args) > 0 {
+			days = append(days, canonicalExpressionNames[expType]+"("+strings.Join(args, ", ")+")")
+		}
+	}
+
+	writeDays(internals.ExpressionTypeDaysOfWeek, irRangesText(internals.ExpressionTypeDaysOfWeek, g.DaysOfWeek, g.DaysOfWeekExcluded))
+	writeDays(internals.ExpressionTypeDaysOfMonth, irRangesText(internals.ExpressionTypeDaysOfMonth, g.DaysOfMonth, g.DaysOfMonthExcluded))
+	days = append(days, irBusinessDaysText(g.BusinessDays, g.BusinessDaysExcluded)...)
+	writeDays(internals.ExpressionTypeDaysOfYear, irRangesText(internals.ExpressionTypeDaysOfYear, g.DaysOfYear, g.DaysOfYearExcluded))
+	writeDays(internals.ExpressionTypeWeeks, irRangesText(internals.ExpressionTypeWeeks, g.Weeks, g.WeeksExcluded))
+	if g.MonthsMask != 0x1ffe {
+		writeDays(internals.ExpressionTypeMonths, irRangesText(internals.ExpressionTypeMonths, maskRanges(uint64(g.MonthsMask), 1, 12), nil))
+	}
+
+	writeDays(internals.ExpressionTypeDates, irDateRangesText(g.Dates, g.DatesExcluded))
+	days = append(days, irPeriodsText(g.Periods)...)
+	writeDays(internals.ExpressionTypeYears, irRangesText(internals.ExpressionTypeYears, g.Years, g.YearsExcluded))
+	writeDays(internals.ExpressionTypeCalendar, irCalendarsText(g.Calendars, g.CalendarsExcluded))
+
+	// Implied rules set unspecified units to zero, except for the units above the largest one which is specified,
+	// which match every value. Specifying milliseconds leaves every unit above them unrestricted.
+	seconds := irRangesText(internals.ExpressionTypeSeconds, maskRanges(g.SecondsMask, 0, 59), nil)
+	minutes := irRangesText(internals.ExpressionTypeMinutes, maskRanges(g.MinutesMask, 0, 59), nil)
+	hours := irRangesText(internals.ExpressionTypeHours, maskRanges(uint64(g.HoursMask), 0, 23), nil)
+	switch {
+	case g.MillisecondsMask != internals.ZeroMilliseconds:
+		write(internals.ExpressionTypeMilliseconds, irRangesText(internals.ExpressionTypeMilliseconds, valueRanges(g.MillisecondsMask.Values(), 0, 999), nil))
+		if g.SecondsMask != 1<<60-1 {
+			write(internals.ExpressionTypeSeconds, seconds)
+		}
+
+		if g.MinutesMask != 1<<60-1 {
+			write(internals.ExpressionTypeMinutes, minutes)
+		}
+
+		if g.HoursMask != 1<<24-1 {
+			write(internals.ExpressionTypeHours, hours)
+		}
+	case g.SecondsMask != 1:
+		write(internals.ExpressionTypeSeconds, seconds)
+		if g.MinutesMask != 1<<60-1 {
+			write(internals.ExpressionTypeMinutes, minutes)
+		}
+
+		if g.HoursMask != 1<<24-1 {
+			write(internals.ExpressionTypeHours, hours)
+		}
+	case g.MinutesMask != 1:
+		write(internals.ExpressionTypeMinutes, minutes)
+		if g.HoursMask != 1<<24-1 {
+			write(internals.ExpressionTypeHours, hours)
+		}
+	case g.HoursMask != 1 || len(days) == 0:
+		write(internals.ExpressionTypeHours, hours)
+	}
+
+	return strings.Join(append(expressions, days...), " ")
+}
+
+// the values which a wildcard expands to for each integer expression type
+var wildcardRanges = map[internals.ExpressionType][2]int{
+	internals.ExpressionTypeMilliseconds: {0, 999},
+	internals.ExpressionTypeSeconds:      {0, 59},
+	internals.ExpressionTypeMinutes:      {0, 59},
+	internals.ExpressionTypeHours:        {0, 23},
+	internals.ExpressionTypeDaysOfWeek:   {1, 7},
+	internals.ExpressionTypeDaysOfMonth:  {1, 31},
+	internals.ExpressionTypeDaysOfYear:   {1, 366},
+	internals.ExpressionTypeBusinessDays: {1, 31},
+	internals.ExpressionTypeWeeks:        {1, 53},
+	internals.ExpressionTypeMonths:       {1, 12},
+	internals.ExpressionTypeYears:        {1900, 2200},
+}
+
+func irRangesText(expType internals.ExpressionType, ranges, excluded []*internals.IrIntegerRange) []string {
+	var args []string
+	for _, r := range ranges {
+		args = append(args, irRangeText(expType, r, false))
+	}
+
+	for _, r := range excluded {
+		args = append(args, irRangeText(expType, r, true))
+	}
+
+	return args
+}
+
+func irRangeText(expType internals.ExpressionType, r *internals.IrIntegerRange, isExclusion bool) string {
+	var sb strings.Builder
+	if isExclusion {
+		sb.WriteString("!")
+	}
+
+	wildcard := wildcardRanges[expType]
+	isWildcard := r.IsRange && !r.IsHalfOpen && r.Start == wildcard[0] && r.End == wildcard[1]
+
+	// excluded wildcards are only valid with an interval
+	if isWildcard && (r.HasInterval || !isExclusion) {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(irValueText(expType, r.Start))
+		if r.IsRange {
+			if r.IsHalfOpen {
+				sb.WriteString("..<")
+			} else {
+				sb.WriteString("..")
+			}
+
+			sb.WriteString(irValueText(expType, r.End))
+		}
+	}
+
+	if r.HasNth {
+		sb.WriteString("#" + strconv.Itoa(r.Nth))
+	}
+
+	if r.HasInterval {
+		sb.WriteString("%" + strconv.Itoa(r.Interval))
+	}
+
+	return sb.String()
+}
+
+func irValueText(expType internals.ExpressionType, value int) string {
+	switch expType {
+	case internals.ExpressionTypeDaysOfWeek:
+		return dayLiterals[value-1]
+	case internals.ExpressionTypeMonths:
+		return monthLiterals[value-1]
+	}
+
+	return strconv.Itoa(value)
+}
+
+func irDateRangesText(ranges, excluded []*internals.IrDateRange) []string {
+	var args []string
+	for _, r := range ranges {
+		args = append(args, irDateRangeText(r, false))
+	}
+
+	for _, r := range excluded {
+		args = append(args, irDateRangeText(r, true))
+	}
+
+	return args
+}
+
+func irDateRangeText(r *internals.IrDateRange, isExclusion bool) string {
+	text := irDateText(r.Start)
+	if r.IsRange {
+		if r.IsHalfOpen {
+			text += "..<"
+		} else {
+			text += ".."
+		}
+
+		text += irDateText(r.End)
+	}
+
+	if r.HasInterval {
+		text += "%" + strconv.Itoa(r.Interval)
+	}
+
+	if isExclusion {
+		text = "!" + text
+	}
+
+	return text
+}
+
+// irPeriodsText returns an every expression for each period.
+func irPeriodsText(periods []*internals.IrPeriod) []string {
+	var expressions []string
+	for _, p := range periods {
+		expressions = append(expressions, canonicalExpressionNames[internals.ExpressionTypeEvery]+"("+strconv.Itoa(p.Days)+"d, from "+irDateText(p.From)+")")
+	}
+
+	return expressions
+}
+
+// irBusinessDaysText returns a businessdays expression for each day of the month that business days count from, in the
+// order they first appear.
+func irBusinessDaysText(ranges, excluded []*internals.IrIntegerRange) []string {
+	var froms []int
+	args := make(map[int][]string)
+	add := func(r *internals.IrIntegerRange, isExclusion bool) {
+		if _, ok := args[r.From]; !ok {
+			froms = append(froms, r.From)
+		}
+
+		args[r.From] = append(args[r.From], irRangeText(internals.ExpressionTypeBusinessDays, r, isExclusion))
+	}
+
+	for _, r := range ranges {
+		add(r, false)
+	}
+
+	for _, r := range excluded {
+		add(r, true)
+	}
+
+	var expressions []string
+	for _, from := range froms {
+		if from != 0 {
+			args[from] = append(args[from], "from "+strconv.Itoa(from))
+		}
+
+		expressions = append(expressions, canonicalExpressionNames[internals.ExpressionTypeBusinessDays]+"("+strings.Join(args[from], ", ")+")")
+	}
+
+	return expressions
+}
+
+func irCalendarsText(names, excluded []string) []string {
+	args := concat(names)
+	for _, name := range excluded {
+		args = append(args, "!"+name)
+	}
+
+	return args
+}
+
+func irDateText(date *internals.IrDate) string {
+	text := strconv.Itoa(date.Month) + "/" + strconv.Itoa(date.Day)
+	if date.Year != 0 {
+		text = strconv.Itoa(date.Year) + "/" + text
+	}
+
+	return text
+}
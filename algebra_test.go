@@ -0,0 +1,337 @@
+package schyntax
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var algebraStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+var algebraEnd = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestSetOperations(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		union     string
+		intersect string
+		subtract  string
+	}{
+		{
+			"dow(mon..fri) h(9..17)", "h(12..20) min(0, 30)",
+			"{h(9..17) dow(mon..fri)}, {min(0, 30) h(12..20)}",
+			"h(12..17) dow(mon..fri)",
+			"h(9..11) dow(mon..fri)",
+		},
+		{
+			"dom(1..15) h(9)", "dom(10..20) dow(mon#1, fri) h(9)",
+			"{h(9) dom(1..15)}, {h(9) dow(mon#1, fri) dom(10..20)}",
+			"h(9) dow(mon#1, fri) dom(10..15)",
+			"{h(9) dow(!mon#1, !fri) dom(1..15)}, {h(9) dom(1..15, !10..20)}",
+		},
+		{
+			"dom(-5..-1)", "dom(-3..-1, -10) h(0, 12)",
+			"{dom(-5..-1)}, {h(0, 12) dom(-3..-1, -10)}",
+			"dom(-3..-1)",
+			"dom(-5..-1, !-3..-1, !-10)",
+		},
+		{
+			"dates(12/20..1/5) h(8)", "dates(2024/12/31..2025/1/2, 12/25) h(8..9)",
+			"{h(8) dates(12/20..1/5)}, {h(8..9) dates(2024/12/31..2025/1/2, 12/25)}",
+			"h(8) dates(2024/12/31..2025/1/2, 12/25)",
+			"h(8) dates(12/20..1/5, !2024/12/31..2025/1/2, !12/25)",
+		},
+		{
+			"months(jan..jun) dow(!sat, !sun) h(*%6)", "months(mar..sep) h(0..11)",
+			"{h(*%6) dow(!sat, !sun) months(jan..jun)}, {h(0..11) months(mar..sep)}",
+			"h(0, 6) dow(!sat, !sun) months(mar..jun)",
+			"{h(12, 18) dow(!sat, !sun) months(jan..jun)}, {h(*%6) dow(!sat, !sun) months(jan..feb)}",
+		},
+		{
+			"ms(0, 500) s(0) min(0) h(9)", "h(9..10)",
+			"{ms(0, 500) s(0) min(0) h(9)}, {h(9..10)}",
+			"h(9)",
+			"ms(500) s(0) min(0) h(9)",
+		},
+		{
+			"dates(1/1) years(2024..2030)", "dates(1/1) years(*%2, !2028)",
+			"{dates(1/1) years(2024..2030)}, {dates(1/1) years(*%2, !2028)}",
+			"dates(1/1) years(2024..2030%2, !2028)",
+			"{dates(1/1) years(2024..2030, !*%2)}, {dates(1/1) years(2028)}",
+		},
+		{
+			"dow(mon) weeks(*%2)", "dow(mon) weeks(1..10)",
+			"{dow(mon) weeks(*%2)}, {dow(mon) weeks(1..10)}",
+			"dow(mon) weeks(1..9%2)",
+			"dow(mon) weeks(*%2, !1..10)",
+		},
+		{
+			"every(10d, from 2024/3/1) h(9)", "h(9..10) dow(mon..fri)",
+			"{h(9) every(10d, from 2024/3/1)}, {h(9..10) dow(mon..fri)}",
+			"h(9) dow(mon..fri) every(10d, from 2024/3/1)",
+			"h(9) dow(!mon..fri) every(10d, from 2024/3/1)",
+		},
+		{
+			"bday(1, -1) h(9)", "h(9..10) dow(mon..wed)",
+			"{h(9) bday(1, -1)}, {h(9..10) dow(mon..wed)}",
+			"h(9) dow(mon..wed) bday(1, -1)",
+			"h(9) dow(!mon..wed) bday(1, -1)",
+		},
+		{
+			"h(9) dow(mon..fri)", "h(9) cal(!us-federal)",
+			"{h(9) dow(mon..fri)}, {h(9) cal(!us-federal)}",
+			"h(9) dow(mon..fri) cal(!us-federal)",
+			"h(9) dow(mon..fri) cal(us-federal)",
+		},
+		{
+			"min(*%15)", "min(*%10) h(*)",
+			"{min(*%15)}, {min(*%10)}",
+			"min(0, 30)",
+			"min(15, 45)",
+		},
+	}
+
+	for _, test := range tests {
+		a, err := New(test.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := New(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		check := func(name string, op func(a, b Schedule) (Schedule, error), expected string, matches func(inA, inB bool) bool) {
+			result, err := op(a, b)
+			if err != nil {
+				t.Errorf("%s(%q, %q): %s", name, test.a, test.b, err)
+				return
+			}
+
+			if result.OriginalText() != expected {
+				t.Errorf("%s(%q, %q). Expected: %q, Actual: %q", name, test.a, test.b, expected, result.OriginalText())
+			}
+
+			assertSetOperation(t, name, a, b, result, matches)
+		}
+
+		check("Union", Union, test.union, func(inA, inB bool) bool { return inA || inB })
+		check("Intersect", Intersect, test.intersect, func(inA, inB bool) bool { return inA && inB })
+		check("Subtract", Subtract, test.subtract, func(inA, inB bool) bool { return inA && !inB })
+	}
+}
+
+// assertSetOperation checks that result fires at exactly the times where matches returns true, over two years.
+func assertSetOperation(t *testing.T, name string, a, b, result Schedule, matches func(inA, inB bool) bool) {
+	inA := make(map[time.Time]bool)
+	for e := range a.Occurrences(algebraStart, algebraEnd) {
+		inA[e] = true
+	}
+
+	inB := make(map[time.Time]bool)
+	for e := range b.Occurrences(algebraStart, algebraEnd) {
+		inB[e] = true
+	}
+
+	fired := make(map[time.Time]bool)
+	for e := range result.Occurrences(algebraStart, algebraEnd) {
+		fired[e] = true
+		if !matches(inA[e], inB[e]) {
+			t.Errorf("%s(%q, %q) fired at %s", name, a.OriginalText(), b.OriginalText(), e)
+			return
+		}
+	}
+
+	for _, events := range []map[time.Time]bool{inA, inB} {
+		for e := range events {
+			if matches(inA[e], inB[e]) && !fired[e] {
+				t.Errorf("%s(%q, %q) didn't fire at %s", name, a.OriginalText(), b.OriginalText(), e)
+				return
+			}
+		}
+	}
+}
+
+func TestSetOperationErrors(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		op    func(a, b Schedule) (Schedule, error)
+		empty bool
+	}{
+		{"h(1)", "h(2)", Intersect, true},
+		{"h(1)", "h(*)", Subtract, true},
+		{"dom(1..5)", "dom(-1)", Intersect, false},
+		{"dow(mon#1)", "dow(mon#-1)", Intersect, false},
+		{"dates(1/1..3/1%7)", "dates(2/1..4/1)", Intersect, false},
+		{"h(9) dom(1..20)", "h(9) dom(!-5..-1)", Subtract, false},
+		{"h(9) cal(business-days)", "h(9) cal(easter)", Intersect, false},
+		{"h(9)", "h(9) every(1d, from 2025/1/1)", Subtract, false},
+		{"h(9) bday(1)", "h(9) bday(2)", Intersect, false},
+		{"h(9)", "h(9) bday(!1)", Subtract, false},
+	}
+
+	for _, test := range tests {
+		a, _ := New(test.a)
+		b, _ := New(test.b)
+		_, err := test.op(a, b)
+		setErr, ok := err.(*SetOperationError)
+		if !ok {
+			t.Errorf("%q, %q: expected a *SetOperationError, got %v", test.a, test.b, err)
+			continue
+		}
+
+		if setErr.Empty() != test.empty || setErr.Input() != test.a {
+			t.Errorf("%q, %q: unexpected error %q (empty: %t, input: %q)", test.a, test.b, setErr, setErr.Empty(), setErr.Input())
+		}
+	}
+
+	a, _ := New("h(1)")
+	b, _ := NewInLocation("h(1)", time.FixedZone("UTC+1", 3600))
+	if _, err := Union(a, b); err == nil {
+		t.Error("Expected schedules in different locations to be rejected")
+	}
+}
+
+// TestSetOperationsMatchEventSets compares the results of the set operations on random schedules against the events of
+// the schedules they were created from.
+func TestSetOperationsMatchEventSets(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	pairs := [][2]string{
+		{"min(15) months(6, !*%4)", "h(7..0) dow(1..<3)"},
+		{"h(11, !18..<13) dow(*%4, 2..<5)", "min(30) dom(1..10)"},
+	}
+
+	for len(pairs) < 150 {
+		pairs = append(pairs, [2]string{randomScheduleText(rng), randomScheduleText(rng)})
+	}
+
+	events := func(sch Schedule) map[time.Time]bool {
+		set := make(map[time.Time]bool)
+		for e := range sch.Occurrences(from, to) {
+			set[e] = true
+		}
+
+		return set
+	}
+
+	for _, pair := range pairs {
+		a, err := New(pair[0])
+		if err != nil {
+			continue
+		}
+
+		b, err := New(pair[1])
+		if err != nil {
+			continue
+		}
+
+		inA, inB := events(a), events(b)
+		for _, op := range []struct {
+			name    string
+			op      func(a, b Schedule) (Schedule, error)
+			matches func(inA, inB bool) bool
+		}{
+			{"Union", Union, func(inA, inB bool) bool { return inA || inB }},
+			{"Intersect", Intersect, func(inA, inB bool) bool { return inA && inB }},
+			{"Subtract", Subtract, func(inA, inB bool) bool { return inA && !inB }},
+		} {
+			expected := make(map[time.Time]bool)
+			for _, set := range []map[time.Time]bool{inA, inB} {
+				for e := range set {
+					if op.matches(inA[e], inB[e]) {
+						expected[e] = true
+					}
+				}
+			}
+
+			result, err := op.op(a, b)
+			if err != nil {
+				if setErr, ok := err.(*SetOperationError); !ok {
+					t.Errorf("%s(%q, %q): %s", op.name, pair[0], pair[1], err)
+				} else if setErr.Empty() && len(expected) > 0 {
+					t.Errorf("%s(%q, %q) is empty, but should fire %d times", op.name, pair[0], pair[1], len(expected))
+				}
+
+				continue
+			}
+
+			fired := events(result)
+			for e := range fired {
+				if !expected[e] {
+					t.Errorf("%s(%q, %q) = %q fired at %s", op.name, pair[0], pair[1], result.OriginalText(), e)
+					break
+				}
+			}
+
+			for e := range expected {
+				if !fired[e] {
+					t.Errorf("%s(%q, %q) = %q didn't fire at %s", op.name, pair[0], pair[1], result.OriginalText(), e)
+					break
+				}
+			}
+		}
+	}
+}
+
+// randomScheduleText returns the text of one or two groups of random expressions, which isn't always valid. Minutes
+// are limited to a few values, so that the schedules don't fire too often to compare.
+func randomScheduleText(rng *rand.Rand) string {
+	units := []struct {
+		name     string
+		min, max int
+	}{
+		{"min", 0, 59},
+		{"h", 0, 23},
+		{"dow", 1, 7},
+		{"dom", 1, 31},
+		{"months", 1, 12},
+	}
+
+	arg := func(name string, min, max int) string {
+		value := func() string { return strconv.Itoa(min + rng.IntN(max-min+1)) }
+		var text string
+		switch rng.IntN(5) {
+		case 0, 1:
+			text = value()
+		case 2:
+			text = value() + ".." + value()
+		case 3:
+			text = value() + "..<" + value()
+		default:
+			text = "*%" + strconv.Itoa(2+rng.IntN(4))
+		}
+
+		if name == "min" && strings.HasPrefix(text, "*") {
+			text = "*%" + strconv.Itoa(10+rng.IntN(20))
+		}
+
+		if rng.IntN(4) == 0 {
+			text = "!" + text
+		}
+
+		return text
+	}
+
+	var groups []string
+	for range 1 + rng.IntN(2) {
+		var expressions []string
+		for _, i := range rng.Perm(len(units))[:1+rng.IntN(3)] {
+			u := units[i]
+			args := []string{arg(u.name, u.min, u.max)}
+			if rng.IntN(2) == 0 {
+				args = append(args, arg(u.name, u.min, u.max))
+			}
+
+			expressions = append(expressions, u.name+"("+strings.Join(args, ", ")+")")
+		}
+
+		groups = append(groups, "{"+strings.Join(expressions, " ")+"}")
+	}
+
+	return strings.Join(groups, ", ")
+}
@@ -0,0 +1,278 @@
+// Package ast exposes the syntax tree of a schyntax schedule, as produced by the same parser which New uses. Trees are
+// immutable: nodes only expose their contents through methods, and tokens are values.
+package ast
+
+import "time"
+
+// Node is implemented by every node in the tree.
+type Node interface {
+	// Pos returns the index of the node's first character in the input (not including leading trivia).
+	Pos() int
+	// End returns the index immediately after the node's last character.
+	End() int
+	// Tokens returns the tokens which belong directly to the node, not including the tokens of its children, in the
+	// order they appear in the input.
+	Tokens() []Token
+}
+
+type nodeBase struct {
+	pos    int
+	end    int
+	tokens []Token
+}
+
+func (n *nodeBase) Pos() int {
+	return n.pos
+}
+
+func (n *nodeBase) End() int {
+	return n.end
+}
+
+func (n *nodeBase) Tokens() []Token {
+	return append([]Token(nil), n.tokens...)
+}
+
+/**********************************************************************************************
+ * Program
+**********************************************************************************************/
+
+// Program is the root of the tree. Its tokens are the commas between groups and free-floating expressions, and the
+// end of input token, whose leading trivia is any whitespace at the end of the input.
+type Program struct {
+	nodeBase
+	input       string
+	items       []Node
+	groups      []*Group
+	expressions []*Expression
+	allTokens   []Token
+}
+
+// Input returns the text which was parsed.
+func (n *Program) Input() string {
+	return n.input
+}
+
+// Items returns the program's groups and free-floating expressions, in the order they appear in the input. Each item
+// is either a *Group or an *Expression.
+func (n *Program) Items() []Node {
+	return append([]Node(nil), n.items...)
+}
+
+// Groups returns the program's groups (expressions inside curly braces).
+func (n *Program) Groups() []*Group {
+	return append([]*Group(nil), n.groups...)
+}
+
+// Expressions returns the program's free-floating expressions (expressions which are not inside curly braces).
+// Together, they form an implicit group.
+func (n *Program) Expressions() []*Expression {
+	return append([]*Expression(nil), n.expressions...)
+}
+
+// AllTokens returns every token in the program, in order. Concatenating the leading trivia and text of each token
+// reproduces the input exactly.
+func (n *Program) AllTokens() []Token {
+	return append([]Token(nil), n.allTokens...)
+}
+
+/**********************************************************************************************
+ * Group
+**********************************************************************************************/
+
+// Group is a list of expressions inside curly braces. Its tokens are the curly braces and any commas between its
+// expressions.
+type Group struct {
+	nodeBase
+	expressions []*Expression
+}
+
+func (n *Group) Expressions() []*Expression {
+	return append([]*Expression(nil), n.expressions...)
+}
+
+/**********************************************************************************************
+ * Expression
+**********************************************************************************************/
+
+// Expression is an expression such as "hours(9..17)". Its tokens are the name, the parentheses and any commas between
+// its arguments.
+type Expression struct {
+	nodeBase
+	kind      ExpressionKind
+	name      Token
+	arguments []*Argument
+}
+
+func (n *Expression) Kind() ExpressionKind {
+	return n.kind
+}
+
+// Name returns the token of the expression's name, as it was written.
+func (n *Expression) Name() Token {
+	return n.name
+}
+
+func (n *Expression) Arguments() []*Argument {
+	return append([]*Argument(nil), n.arguments...)
+}
+
+/**********************************************************************************************
+ * Argument
+**********************************************************************************************/
+
+// Argument is one argument of an expression, such as "!mon..fri%2". Its tokens are the ! operator, wildcard, # operator
+// and % operator, if present.
+type Argument struct {
+	nodeBase
+	isExclusion bool
+	isWildcard  bool
+	isFrom      bool
+	valueRange  *Range
+	nth         *IntegerValue
+	interval    *IntegerValue
+}
+
+// IsExclusion returns true if the argument is preceded by the ! operator.
+func (n *Argument) IsExclusion() bool {
+	return n.isExclusion
+}
+
+func (n *Argument) IsWildcard() bool {
+	return n.isWildcard
+}
+
+// IsFrom returns true if the argument is the from date of an every expression, or the from day of a businessdays
+// expression.
+func (n *Argument) IsFrom() bool {
+	return n.isFrom
+}
+
+// Range returns the argument's value or range, or nil if the argument is a wildcard.
+func (n *Argument) Range() *Range {
+	return n.valueRange
+}
+
+// Nth returns the value after the # operator, or nil if there isn't one.
+func (n *Argument) Nth() *IntegerValue {
+	return n.nth
+}
+
+// Interval returns the value after the % operator, or nil if there isn't one.
+func (n *Argument) Interval() *IntegerValue {
+	return n.interval
+}
+
+/**********************************************************************************************
+ * Range
+**********************************************************************************************/
+
+// Range is a single value, or a range of values. Its token is the range operator, if present.
+type Range struct {
+	nodeBase
+	start      Value
+	end        Value
+	isHalfOpen bool
+}
+
+func (n *Range) Start() Value {
+	return n.start
+}
+
+// EndValue returns the end of the range, or nil if the range is a single value.
+func (n *Range) EndValue() Value {
+	return n.end
+}
+
+// IsRange returns true if the node has an end value, rather than being a single value.
+func (n *Range) IsRange() bool {
+	return n.end != nil
+}
+
+// IsHalfOpen returns true if the range uses the ..< operator.
+func (n *Range) IsHalfOpen() bool {
+	return n.isHalfOpen
+}
+
+/**********************************************************************************************
+ * Values
+**********************************************************************************************/
+
+// Value is an *IntegerValue, a *DateValue, a *DurationValue or a *CalendarValue.
+type Value interface {
+	Node
+	isValue()
+}
+
+// IntegerValue is an integer, or a day or month literal. Day literals have values from 1 (Sunday) to 7 (Saturday), and
+// month literals from 1 (January) to 12 (December).
+type IntegerValue struct {
+	nodeBase
+	value int
+}
+
+func (n *IntegerValue) isValue() {}
+
+func (n *IntegerValue) Value() int {
+	return n.value
+}
+
+// DateValue is a date, with or without a year. Its tokens are the numbers and the slashes between them.
+type DateValue struct {
+	nodeBase
+	hasYear bool
+	year    int
+	month   int
+	day     int
+}
+
+func (n *DateValue) isValue() {}
+
+func (n *DateValue) HasYear() bool {
+	return n.hasYear
+}
+
+// Year returns the date's year, or zero if the date doesn't have one.
+func (n *DateValue) Year() int {
+	return n.year
+}
+
+func (n *DateValue) Month() int {
+	return n.month
+}
+
+func (n *DateValue) Day() int {
+	return n.day
+}
+
+// DurationValue is the argument of a jitter expression, such as "30s". Its tokens are the number and the unit.
+type DurationValue struct {
+	nodeBase
+	value        int
+	milliseconds int
+}
+
+func (n *DurationValue) isValue() {}
+
+// Value returns the number before the unit.
+func (n *DurationValue) Value() int {
+	return n.value
+}
+
+// Duration returns the length of the duration.
+func (n *DurationValue) Duration() time.Duration {
+	return time.Duration(n.milliseconds) * time.Millisecond
+}
+
+// CalendarValue is the argument of a calendar expression, such as "us-federal".
+type CalendarValue struct {
+	nodeBase
+	name string
+}
+
+func (n *CalendarValue) isValue() {}
+
+// Name returns the calendar's name, in lower case.
+func (n *CalendarValue) Name() string {
+	return n.name
+}
@@ -0,0 +1,200 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+func TestParse(t *testing.T) {
+	input := " min(*%15), { days(!mon..<fri, tue#2) dates(2025/1/1) } h(9) "
+	program, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := program.Items()
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+
+	if len(program.Expressions()) != 2 || len(program.Groups()) != 1 {
+		t.Fatalf("Expected 2 expressions and 1 group, got %d and %d", len(program.Expressions()), len(program.Groups()))
+	}
+
+	minutes := items[0].(*Expression)
+	if minutes.Kind() != Minutes || minutes.Name().Text != "min" || minutes.Pos() != 1 || minutes.End() != 10 {
+		t.Errorf("Unexpected minutes expression: %s %q %d..%d", minutes.Kind(), minutes.Name().Text, minutes.Pos(), minutes.End())
+	}
+
+	wildcard := minutes.Arguments()[0]
+	if !wildcard.IsWildcard() || wildcard.Range() != nil || wildcard.Interval().Value() != 15 {
+		t.Error("Expected a wildcard with an interval of 15")
+	}
+
+	group := items[1].(*Group)
+	if group.Pos() != 12 || group.End() != 55 || input[group.Pos():group.End()] != "{ days(!mon..<fri, tue#2) dates(2025/1/1) }" {
+		t.Errorf("Unexpected group position %d..%d", group.Pos(), group.End())
+	}
+
+	days := group.Expressions()[0]
+	if days.Kind() != DaysOfWeek {
+		t.Errorf("Expected DaysOfWeek, got %s", days.Kind())
+	}
+
+	excluded := days.Arguments()[0]
+	r := excluded.Range()
+	if !excluded.IsExclusion() || !r.IsRange() || !r.IsHalfOpen() || r.Start().(*IntegerValue).Value() != 2 || r.EndValue().(*IntegerValue).Value() != 6 {
+		t.Error("Expected !mon..<fri")
+	}
+
+	nth := days.Arguments()[1]
+	if nth.Nth().Value() != 2 || nth.Range().IsRange() {
+		t.Error("Expected tue#2")
+	}
+
+	date := group.Expressions()[1].Arguments()[0].Range().Start().(*DateValue)
+	if !date.HasYear() || date.Year() != 2025 || date.Month() != 1 || date.Day() != 1 || len(date.Tokens()) != 5 {
+		t.Errorf("Unexpected date %d/%d/%d", date.Year(), date.Month(), date.Day())
+	}
+
+	if hours := items[2].(*Expression); hours.Kind() != Hours {
+		t.Errorf("Expected Hours, got %s", hours.Kind())
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	program, err := Parse("h(9) jitter(90 sec)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jitter := program.Expressions()[1]
+	duration := jitter.Arguments()[0].Range().Start().(*DurationValue)
+	if jitter.Kind() != Jitter || duration.Value() != 90 || duration.Duration() != 90*time.Second || duration.Pos() != 12 || duration.End() != 18 {
+		t.Errorf("Unexpected duration %d (%s) at %d..%d", duration.Value(), duration.Duration(), duration.Pos(), duration.End())
+	}
+
+	if tokens := duration.Tokens(); len(tokens) != 2 || tokens[1].Kind != TokenDurationUnit || tokens[1].LeadingTrivia != " " {
+		t.Errorf("Unexpected tokens %v", tokens)
+	}
+}
+
+func TestParseEvery(t *testing.T) {
+	program, err := Parse("every(2w, from 2025/1/6)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	every := program.Expressions()[0]
+	period, from := every.Arguments()[0], every.Arguments()[1]
+	if every.Kind() != Every || period.IsFrom() || !from.IsFrom() || period.Range().Start().(*DurationValue).Duration() != 14*24*time.Hour {
+		t.Errorf("Unexpected every expression %v", every)
+	}
+
+	if date := from.Range().Start().(*DateValue); date.Year() != 2025 || date.Pos() != 15 || from.Tokens()[0].Kind != TokenFrom {
+		t.Errorf("Unexpected from date %v", date)
+	}
+}
+
+func TestParseBusinessDays(t *testing.T) {
+	program, err := Parse("bday(-1, from 15)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bday := program.Expressions()[0]
+	day, from := bday.Arguments()[0], bday.Arguments()[1]
+	if bday.Kind() != BusinessDays || day.IsFrom() || !from.IsFrom() || from.Range().Start().(*IntegerValue).Value() != 15 {
+		t.Errorf("Unexpected businessdays expression %v", bday)
+	}
+}
+
+func TestTokensReproduceInput(t *testing.T) {
+	inputs := []string{
+		" min(*%15), { days(!mon..<fri, tue#2) dates(2025/1/1) } h(9) ",
+		"{s(0)}{m(1)}, dom( -1 , 5..7 )\t",
+		"months(JAN..mar%2)",
+		"every( 2w ,from  2025/1/6) cal(Business-Days)",
+		"bday( -1 , from 15 )",
+	}
+
+	for _, input := range inputs {
+		program, err := Parse(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sb strings.Builder
+		for _, tok := range program.AllTokens() {
+			sb.WriteString(tok.LeadingTrivia + tok.Text)
+		}
+
+		if sb.String() != input {
+			t.Errorf("Expected %q, got %q", input, sb.String())
+		}
+
+		last := program.AllTokens()[len(program.AllTokens())-1]
+		if last.Kind != TokenEndOfInput || last.Pos != len(input) {
+			t.Errorf("%q: expected the last token to be the end of input, got %s at %d", input, last.Kind, last.Pos)
+		}
+	}
+}
+
+func TestInspect(t *testing.T) {
+	program, err := Parse("{h(9..17) min(0)}, dow(mon)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	Inspect(program, func(node Node) bool {
+		switch n := node.(type) {
+		case *Expression:
+			visited = append(visited, n.Kind().String())
+		case *IntegerValue:
+			visited = append(visited, program.Input()[n.Pos():n.End()])
+		}
+
+		return true
+	})
+
+	expected := "Hours 9 17 Minutes 0 DaysOfWeek mon"
+	if actual := strings.Join(visited, " "); actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+
+	// returning false skips the node's children
+	count := 0
+	Inspect(program, func(node Node) bool {
+		if node != nil {
+			count++
+		}
+
+		_, isGroup := node.(*Group)
+		return !isGroup
+	})
+
+	if count != 6 { // program, group, and dow's expression, argument, range and value
+		t.Errorf("Expected 6 nodes, got %d", count)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse("min(1..)")
+	parseError, ok := err.(*internals.ParseError)
+	if !ok {
+		t.Fatalf("Expected a *ParseError, got %v", err)
+	}
+
+	if parseError.Index() != 7 {
+		t.Errorf("Expected index 7, got %d", parseError.Index())
+	}
+
+	// values aren't validated
+	if _, err := Parse("min(99)"); err != nil {
+		t.Error(err)
+	}
+}
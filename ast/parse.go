@@ -0,0 +1,196 @@
+package ast
+
+import (
+	"sort"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// Parse parses a schedule into a syntax tree. Errors are returned as an *internals.ParseError. Parse only checks the
+// syntax of the schedule, so values may still be out of range; schyntax.New performs the complete validation.
+func Parse(input string) (program *Program, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			parseError, ok := e.(*internals.ParseError)
+			if !ok {
+				panic(e)
+			}
+
+			program = nil
+			err = parseError
+		}
+	}()
+
+	node := internals.NewParser(input).Parse()
+
+	c := &converter{}
+	program = c.program(input, node)
+	return program, nil
+}
+
+type converter struct {
+	tokens []Token
+}
+
+func (c *converter) token(tok *internals.Token) Token {
+	t := Token{tokenKinds[tok.Type], tok.RawValue, tok.Index, tok.LeadingTrivia}
+	c.tokens = append(c.tokens, t)
+	return t
+}
+
+func (c *converter) base(tokens []*internals.Token, children ...Node) nodeBase {
+	b := nodeBase{pos: -1, end: -1}
+	for _, tok := range tokens {
+		t := c.token(tok)
+		b.tokens = append(b.tokens, t)
+		b.extend(t.Pos, t.End())
+	}
+
+	for _, child := range children {
+		b.extend(child.Pos(), child.End())
+	}
+
+	sort.Slice(b.tokens, func(i, j int) bool {
+		return b.tokens[i].Pos < b.tokens[j].Pos
+	})
+
+	return b
+}
+
+func (b *nodeBase) extend(pos, end int) {
+	if b.pos == -1 || pos < b.pos {
+		b.pos = pos
+	}
+
+	if end > b.end {
+		b.end = end
+	}
+}
+
+func (c *converter) program(input string, node *internals.ProgramNode) *Program {
+	n := &Program{input: input}
+
+	var children []Node
+	for _, exp := range node.Expressions {
+		e := c.expression(exp)
+		n.expressions = append(n.expressions, e)
+		children = append(children, e)
+	}
+
+	for _, group := range node.Groups {
+		g := c.group(group)
+		n.groups = append(n.groups, g)
+		children = append(children, g)
+	}
+
+	n.nodeBase = c.base(node.Tokens, children...)
+	n.pos = 0
+	n.end = len(input)
+
+	n.items = children
+	sort.SliceStable(n.items, func(i, j int) bool {
+		return n.items[i].Pos() < n.items[j].Pos()
+	})
+
+	n.allTokens = c.tokens
+	sort.Slice(n.allTokens, func(i, j int) bool {
+		return n.allTokens[i].Pos < n.allTokens[j].Pos
+	})
+
+	return n
+}
+
+func (c *converter) group(node *internals.GroupNode) *Group {
+	n := &Group{}
+
+	var children []Node
+	for _, exp := range node.Expressions {
+		e := c.expression(exp)
+		n.expressions = append(n.expressions, e)
+		children = append(children, e)
+	}
+
+	n.nodeBase = c.base(node.Tokens, children...)
+	return n
+}
+
+func (c *converter) expression(node *internals.ExpressionNode) *Expression {
+	n := &Expression{kind: expressionKinds[node.ExpressionType]}
+
+	var children []Node
+	for _, arg := range node.Arguments {
+		a := c.argument(arg)
+		n.arguments = append(n.arguments, a)
+		children = append(children, a)
+	}
+
+	tokens := append([]*internals.Token{node.NameToken}, node.Tokens...)
+	n.nodeBase = c.base(tokens, children...)
+	n.name = n.tokens[0]
+	return n
+}
+
+func (c *converter) argument(node *internals.ArgumentNode) *Argument {
+	n := &Argument{isExclusion: node.IsExclusion, isWildcard: node.IsWildcard, isFrom: node.IsFrom}
+
+	var children []Node
+	if node.Range != nil {
+		n.valueRange = c.valueRange(node.Range)
+		children = append(children, n.valueRange)
+	}
+
+	if node.Nth != nil {
+		n.nth = c.integerValue(node.Nth)
+		children = append(children, n.nth)
+	}
+
+	if node.Interval != nil {
+		n.interval = c.integerValue(node.Interval)
+		children = append(children, n.interval)
+	}
+
+	n.nodeBase = c.base(node.Tokens, children...)
+	return n
+}
+
+func (c *converter) valueRange(node *internals.RangeNode) *Range {
+	n := &Range{isHalfOpen: node.IsHalfOpen}
+
+	n.start = c.value(node.Start)
+	children := []Node{n.start}
+	if node.End != nil {
+		n.end = c.value(node.End)
+		children = append(children, n.end)
+	}
+
+	n.nodeBase = c.base(node.Tokens, children...)
+	return n
+}
+
+func (c *converter) value(node internals.ValueNode) Value {
+	if date, ok := node.(*internals.DateValueNode); ok {
+		n := &DateValue{hasYear: date.HasYear, year: date.Year, month: date.Month, day: date.Day}
+		n.nodeBase = c.base(date.Tokens)
+		return n
+	}
+
+	if duration, ok := node.(*internals.DurationValueNode); ok {
+		n := &DurationValue{value: duration.Value, milliseconds: duration.Milliseconds()}
+		n.nodeBase = c.base(duration.Tokens)
+		return n
+	}
+
+	if calendar, ok := node.(*internals.CalendarValueNode); ok {
+		n := &CalendarValue{name: calendar.Name}
+		n.nodeBase = c.base(calendar.Tokens)
+		return n
+	}
+
+	return c.integerValue(node.(*internals.IntegerValueNode))
+}
+
+func (c *converter) integerValue(node *internals.IntegerValueNode) *IntegerValue {
+	n := &IntegerValue{value: node.Value}
+	n.nodeBase = c.base(node.Tokens)
+	return n
+}
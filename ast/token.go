@@ -0,0 +1,170 @@
+package ast
+
+import (
+	"strconv"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// Token is a single token of the input.
+type Token struct {
+	Kind TokenKind
+	// Text is the token exactly as it appears in the input.
+	Text string
+	// Pos is the index of the token's first character in the input.
+	Pos int
+	// LeadingTrivia is the whitespace between the previous token and this one.
+	LeadingTrivia string
+}
+
+// End returns the index immediately after the token's last character.
+func (t Token) End() int {
+	return t.Pos + len(t.Text)
+}
+
+type TokenKind int
+
+const (
+	TokenEndOfInput TokenKind = iota
+	TokenRangeInclusive
+	TokenRangeHalfOpen
+	TokenInterval
+	TokenNot
+	TokenOpenParen
+	TokenCloseParen
+	TokenOpenCurly
+	TokenCloseCurly
+	TokenForwardSlash
+	TokenComma
+	TokenWildcard
+	TokenNth
+	TokenPositiveInteger
+	TokenNegativeInteger
+	TokenExpressionName
+	TokenDayLiteral
+	TokenMonthLiteral
+	TokenDurationUnit
+	TokenCalendarName
+	TokenFrom
+)
+
+var tokenKinds = map[internals.TokenType]TokenKind{
+	internals.TokenTypeEndOfInput:      TokenEndOfInput,
+	internals.TokenTypeRangeInclusive:  TokenRangeInclusive,
+	internals.TokenTypeRangeHalfOpen:   TokenRangeHalfOpen,
+	internals.TokenTypeInterval:        TokenInterval,
+	internals.TokenTypeNot:             TokenNot,
+	internals.TokenTypeOpenParen:       TokenOpenParen,
+	internals.TokenTypeCloseParen:      TokenCloseParen,
+	internals.TokenTypeOpenCurly:       TokenOpenCurly,
+	internals.TokenTypeCloseCurly:      TokenCloseCurly,
+	internals.TokenTypeForwardSlash:    TokenForwardSlash,
+	internals.TokenTypeComma:           TokenComma,
+	internals.TokenTypeWildcard:        TokenWildcard,
+	internals.TokenTypeNth:             TokenNth,
+	internals.TokenTypePositiveInteger: TokenPositiveInteger,
+	internals.TokenTypeNegativeInteger: TokenNegativeInteger,
+	internals.TokenTypeExpressionName:  TokenExpressionName,
+	internals.TokenTypeDayLiteral:      TokenDayLiteral,
+	internals.TokenTypeMonthLiteral:    TokenMonthLiteral,
+	internals.TokenTypeDurationUnit:    TokenDurationUnit,
+	internals.TokenTypeCalendarName:    TokenCalendarName,
+	internals.TokenTypeFrom:            TokenFrom,
+}
+
+var tokenKindNames = []string{
+	"EndOfInput",
+	"RangeInclusive",
+	"RangeHalfOpen",
+	"Interval",
+	"Not",
+	"OpenParen",
+	"CloseParen",
+	"OpenCurly",
+	"CloseCurly",
+	"ForwardSlash",
+	"Comma",
+	"Wildcard",
+	"Nth",
+	"PositiveInteger",
+	"NegativeInteger",
+	"ExpressionName",
+	"DayLiteral",
+	"MonthLiteral",
+	"DurationUnit",
+	"CalendarName",
+	"From",
+}
+
+func (k TokenKind) String() string {
+	if k < 0 || int(k) >= len(tokenKindNames) {
+		return "TokenKind(" + strconv.Itoa(int(k)) + ")"
+	}
+
+	return tokenKindNames[k]
+}
+
+// ExpressionKind is the type of an expression, which determines the unit its arguments are in.
+type ExpressionKind int
+
+const (
+	Seconds ExpressionKind = iota
+	Minutes
+	Hours
+	DaysOfWeek
+	DaysOfMonth
+	DaysOfYear
+	Months
+	Dates
+	Milliseconds
+	Jitter
+	Calendar
+	Years
+	Weeks
+	Every
+	BusinessDays
+)
+
+var expressionKinds = map[internals.ExpressionType]ExpressionKind{
+	internals.ExpressionTypeSeconds:      Seconds,
+	internals.ExpressionTypeMinutes:      Minutes,
+	internals.ExpressionTypeHours:        Hours,
+	internals.ExpressionTypeDaysOfWeek:   DaysOfWeek,
+	internals.ExpressionTypeDaysOfMonth:  DaysOfMonth,
+	internals.ExpressionTypeDaysOfYear:   DaysOfYear,
+	internals.ExpressionTypeMonths:       Months,
+	internals.ExpressionTypeDates:        Dates,
+	internals.ExpressionTypeMilliseconds: Milliseconds,
+	internals.ExpressionTypeJitter:       Jitter,
+	internals.ExpressionTypeCalendar:     Calendar,
+	internals.ExpressionTypeYears:        Years,
+	internals.ExpressionTypeWeeks:        Weeks,
+	internals.ExpressionTypeEvery:        Every,
+	internals.ExpressionTypeBusinessDays: BusinessDays,
+}
+
+var expressionKindNames = []string{
+	"Seconds",
+	"Minutes",
+	"Hours",
+	"DaysOfWeek",
+	"DaysOfMonth",
+	"DaysOfYear",
+	"Months",
+	"Dates",
+	"Milliseconds",
+	"Jitter",
+	"Calendar",
+	"Years",
+	"Weeks",
+	"Every",
+	"BusinessDays",
+}
+
+func (k ExpressionKind) String() string {
+	if k < 0 || int(k) >= len(expressionKindNames) {
+		return "ExpressionKind(" + strconv.Itoa(int(k)) + ")"
+	}
+
+	return expressionKindNames[k]
+}
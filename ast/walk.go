@@ -0,0 +1,71 @@
+package ast
+
+// Visitor's Visit method is called for each node encountered by Walk. If the result is not nil, Walk visits each of the
+// node's children with it, followed by a call of Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree in depth-first order, visiting children in the order they appear in the input.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	for _, child := range children(node) {
+		Walk(v, child)
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect traverses the tree in depth-first order, calling f for each node. If f returns true, Inspect visits the
+// node's children, followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+func children(node Node) []Node {
+	var nodes []Node
+	switch n := node.(type) {
+	case *Program:
+		nodes = n.items
+	case *Group:
+		for _, exp := range n.expressions {
+			nodes = append(nodes, exp)
+		}
+	case *Expression:
+		for _, arg := range n.arguments {
+			nodes = append(nodes, arg)
+		}
+	case *Argument:
+		if n.valueRange != nil {
+			nodes = append(nodes, n.valueRange)
+		}
+
+		if n.nth != nil {
+			nodes = append(nodes, n.nth)
+		}
+
+		if n.interval != nil {
+			nodes = append(nodes, n.interval)
+		}
+	case *Range:
+		nodes = append(nodes, n.start)
+		if n.end != nil {
+			nodes = append(nodes, n.end)
+		}
+	}
+
+	return nodes
+}
@@ -0,0 +1,303 @@
+package schyntax
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// BuilderValue is the type of the values of an expression's arguments: int for milliseconds, seconds, minutes, hours,
+// days of the month, business days, days of the year, weeks and years, time.Weekday for days of the week, time.Month
+// for months, Date for dates, and CalendarName for calendars.
+type BuilderValue interface {
+	int | time.Weekday | time.Month | Date | CalendarName
+}
+
+// CalendarName is the value of a calendars argument: the name of a built-in calendar, or of one registered with
+// WithCalendar.
+type CalendarName string
+
+// Date is the value of a dates argument. If Year is zero, the date matches every year.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// Arg is a single argument of an expression created for a Builder. Args are immutable; the methods which modify an
+// argument return a copy.
+type Arg[T BuilderValue] struct {
+	start       T
+	end         T
+	isRange     bool
+	isHalfOpen  bool
+	isWildcard  bool
+	isExclusion bool
+	hasNth      bool
+	nth         int
+	hasInterval bool
+	interval    int
+}
+
+// Value returns an argument which matches a single value.
+func Value[T BuilderValue](value T) Arg[T] {
+	return Arg[T]{start: value}
+}
+
+// Range returns an argument which matches the values from start to end, inclusive. If end is less than start, the range
+// wraps around, just like "start..end" in a schedule.
+func Range[T BuilderValue](start, end T) Arg[T] {
+	return Arg[T]{start: start, end: end, isRange: true}
+}
+
+// HalfOpenRange returns an argument which matches the values from start up to, but not including, end. Start and end
+// cannot be equal.
+func HalfOpenRange[T BuilderValue](start, end T) Arg[T] {
+	return Arg[T]{start: start, end: end, isRange: true, isHalfOpen: true}
+}
+
+// Wildcard returns an argument which matches every value. It is usually combined with Every, such as
+// Wildcard[int]().Every(15).
+func Wildcard[T BuilderValue]() Arg[T] {
+	return Arg[T]{isWildcard: true}
+}
+
+// NthDay returns an argument which matches the nth occurrence of day within the month. Negative values of n count
+// from the end of the month.
+func NthDay(day time.Weekday, n int) Arg[time.Weekday] {
+	return Arg[time.Weekday]{start: day, hasNth: true, nth: n}
+}
+
+// Every returns a copy of the argument which only matches every interval values, starting from the start of its range.
+func (a Arg[T]) Every(interval int) Arg[T] {
+	a.hasInterval = true
+	a.interval = interval
+	return a
+}
+
+// Not returns a copy of the argument which excludes its values, rather than including them.
+func (a Arg[T]) Not() Arg[T] {
+	a.isExclusion = true
+	return a
+}
+
+func (a Arg[T]) String() string {
+	var sb strings.Builder
+	if a.isExclusion {
+		sb.WriteString("!")
+	}
+
+	if a.isWildcard {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(builderValueText(a.start))
+		if a.isRange {
+			if a.isHalfOpen {
+				sb.WriteString("..<")
+			} else {
+				sb.WriteString("..")
+			}
+
+			sb.WriteString(builderValueText(a.end))
+		}
+	}
+
+	if a.hasNth {
+		sb.WriteString("#" + strconv.Itoa(a.nth))
+	}
+
+	if a.hasInterval {
+		sb.WriteString("%" + strconv.Itoa(a.interval))
+	}
+
+	return sb.String()
+}
+
+// builderValueText returns the canonical text of a value. Days of the week and months which are out of range are
+// written as integers, so that validation reports them.
+func builderValueText[T BuilderValue](value T) string {
+	switch v := any(value).(type) {
+	case time.Weekday:
+		if v >= time.Sunday && v <= time.Saturday {
+			return dayLiterals[v]
+		}
+
+		return strconv.Itoa(int(v) + 1)
+	case time.Month:
+		if v >= time.January && v <= time.December {
+			return monthLiterals[v-1]
+		}
+
+		return strconv.Itoa(int(v))
+	case Date:
+		text := strconv.Itoa(int(v.Month)) + "/" + strconv.Itoa(v.Day)
+		if v.Year != 0 {
+			text = strconv.Itoa(v.Year) + "/" + text
+		}
+
+		return text
+	case CalendarName:
+		return string(v)
+	}
+
+	return strconv.Itoa(any(value).(int))
+}
+
+// Expression is a single expression created for a Builder, such as Hours(Range(9, 17)).
+type Expression struct {
+	expType internals.ExpressionType
+	args    []string
+}
+
+func newExpression[T BuilderValue](expType internals.ExpressionType, args []Arg[T]) Expression {
+	exp := Expression{expType: expType, args: make([]string, len(args))}
+	for i, arg := range args {
+		exp.args[i] = arg.String()
+	}
+
+	return exp
+}
+
+func Milliseconds(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeMilliseconds, args)
+}
+
+func Seconds(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeSeconds, args)
+}
+
+func Minutes(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeMinutes, args)
+}
+
+func Hours(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeHours, args)
+}
+
+func DaysOfWeek(args ...Arg[time.Weekday]) Expression {
+	return newExpression(internals.ExpressionTypeDaysOfWeek, args)
+}
+
+func DaysOfMonth(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeDaysOfMonth, args)
+}
+
+// NthBusinessDays matches the nth business days of the month, such as NthBusinessDays(Value(3)) for the 3rd. Negative
+// values count back from the last business day. It's written as a bday expression.
+func NthBusinessDays(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeBusinessDays, args)
+}
+
+// NthBusinessDaysFrom is the same as NthBusinessDays, except that positive values count forward from day, and negative
+// values count back from the day before it. For example, NthBusinessDaysFrom(15, Value(-1)) is the last business day
+// before the 15th.
+func NthBusinessDaysFrom(day int, args ...Arg[int]) Expression {
+	exp := newExpression(internals.ExpressionTypeBusinessDays, args)
+	exp.args = append(exp.args, "from "+strconv.Itoa(day))
+	return exp
+}
+
+func DaysOfYear(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeDaysOfYear, args)
+}
+
+// Weeks matches ISO 8601 weeks of the year, from 1 to 53. Negative weeks count back from the last week of the year.
+func Weeks(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeWeeks, args)
+}
+
+func Months(args ...Arg[time.Month]) Expression {
+	return newExpression(internals.ExpressionTypeMonths, args)
+}
+
+func Dates(args ...Arg[Date]) Expression {
+	return newExpression(internals.ExpressionTypeDates, args)
+}
+
+func Years(args ...Arg[int]) Expression {
+	return newExpression(internals.ExpressionTypeYears, args)
+}
+
+// Every matches every days days, counting continuously from the date from, which must have a year. Dates before from
+// don't match.
+func Every(days int, from Date) Expression {
+	period := strconv.Itoa(days) + "d"
+	if days > 0 && days%7 == 0 {
+		period = strconv.Itoa(days/7) + "w"
+	}
+
+	return Expression{expType: internals.ExpressionTypeEvery, args: []string{period, "from " + builderValueText(from)}}
+}
+
+// Calendars matches the dates in any of the named calendars. Only Value and Not arguments are valid.
+func Calendars(args ...Arg[CalendarName]) Expression {
+	return newExpression(internals.ExpressionTypeCalendar, args)
+}
+
+// Jitter delays every event of the group by an offset of less than d, which is derived from the key passed to
+// Schedule.ForKey. d is rounded down to a whole millisecond.
+func Jitter(d time.Duration) Expression {
+	ms := int(d / time.Millisecond)
+	text := strconv.Itoa(ms) + "ms"
+	for _, unit := range []struct {
+		ms   int
+		text string
+	}{{60 * 60 * 1000, "h"}, {60 * 1000, "min"}, {1000, "s"}} {
+		if ms > 0 && ms%unit.ms == 0 {
+			text = strconv.Itoa(ms/unit.ms) + unit.text
+			break
+		}
+	}
+
+	return Expression{expType: internals.ExpressionTypeJitter, args: []string{text}}
+}
+
+func (e Expression) String() string {
+	return canonicalExpressionNames[e.expType] + "(" + strings.Join(e.args, ", ") + ")"
+}
+
+// Builder assembles a schedule from typed expressions, rather than from text. The zero value is an empty builder.
+type Builder struct {
+	groups [][]Expression
+}
+
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Group adds a group of expressions to the schedule. A group matches the times which match all of its expressions, and
+// the schedule matches the times which match any of its groups. Empty groups are ignored.
+func (b *Builder) Group(expressions ...Expression) *Builder {
+	if len(expressions) > 0 {
+		b.groups = append(b.groups, append([]Expression(nil), expressions...))
+	}
+
+	return b
+}
+
+// String returns the canonical text of the schedule, in the same form as Format. If there is only one group, it is
+// written without curly braces.
+func (b *Builder) String() string {
+	groups := make([]string, len(b.groups))
+	for i, group := range b.groups {
+		expressions := make([]string, len(group))
+		for j, exp := range group {
+			expressions[j] = exp.String()
+		}
+
+		groups[i] = strings.Join(expressions, " ")
+		if len(b.groups) > 1 {
+			groups[i] = "{" + groups[i] + "}"
+		}
+	}
+
+	return strings.Join(groups, ", ")
+}
+
+// Build validates the schedule and creates it, exactly as if the text returned by String had been passed to New. The
+// indexes of any errors refer to that text.
+func (b *Builder) Build(options ...Option) (Schedule, error) {
+	return New(b.String(), options...)
+}
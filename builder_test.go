@@ -0,0 +1,87 @@
+package schyntax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		builder  *Builder
+		expected string
+	}{
+		{
+			NewBuilder().Group(
+				DaysOfWeek(Range(time.Monday, time.Friday), Value(time.Wednesday).Not()),
+				Hours(HalfOpenRange(9, 17)),
+				Minutes(Wildcard[int]().Every(15)),
+			),
+			"dow(mon..fri, !wed) h(9..<17) min(*%15)",
+		},
+		{
+			NewBuilder().
+				Group(Dates(Range(Date{Month: time.December, Day: 24}, Date{Month: time.December, Day: 26})), Hours(Value(0))).
+				Group().
+				Group(DaysOfWeek(NthDay(time.Sunday, 2), NthDay(time.Friday, -1)), Months(Range(time.January, time.March).Every(2))),
+			"{dates(12/24..12/26) h(0)}, {dow(sun#2, fri#-1) months(jan..mar%2)}",
+		},
+		{
+			NewBuilder().Group(Seconds(Value(30)), DaysOfMonth(Value(-1)), DaysOfYear(Range(-7, -1).Not()), Dates(Value(Date{2030, time.July, 4}))),
+			"s(30) dom(-1) doy(!-7..-1) dates(2030/7/4)",
+		},
+	}
+
+	for _, test := range tests {
+		text := test.builder.String()
+		if text != test.expected {
+			t.Errorf("Expected: %q, Actual: %q", test.expected, text)
+			continue
+		}
+
+		if formatted, err := Format(text); err != nil || formatted != text {
+			t.Errorf("%q is not canonical. Format returned %q, %v", text, formatted, err)
+		}
+
+		sch, err := test.builder.Build()
+		if err != nil {
+			t.Errorf("%q: %s", text, err)
+			continue
+		}
+
+		if sch.OriginalText() != text {
+			t.Errorf("Expected the original text to be %q, got %q", text, sch.OriginalText())
+		}
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	tests := []struct {
+		builder *Builder
+		index   int
+	}{
+		{NewBuilder().Group(Minutes(Range(0, 30).Every(0))), 9},
+		{NewBuilder().Group(Hours(HalfOpenRange(9, 9))), 2},
+		{NewBuilder().Group(Hours(Value(24))), 2},
+		{NewBuilder().Group(DaysOfWeek(Value(time.Weekday(7)))), 4},
+		{NewBuilder().Group(Months(Value(time.Month(13)))), 7},
+		{NewBuilder().Group(Dates(Value(Date{Month: time.February, Day: 30}))), 6},
+		{NewBuilder().Group(Seconds(Wildcard[int]().Not())), 2},
+		{NewBuilder().Group(Hours()), 2},
+		{NewBuilder(), 0},
+	}
+
+	for _, test := range tests {
+		_, err := test.builder.Build()
+		parseError, ok := err.(*internals.ParseError)
+		if !ok {
+			t.Errorf("%q: expected a *ParseError, got %v", test.builder.String(), err)
+			continue
+		}
+
+		if parseError.Index() != test.index {
+			t.Errorf("%q: expected an error at index %d, got %d: %s", test.builder.String(), test.index, parseError.Index(), parseError)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package schyntax
+
+import (
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// the days of the week which aren't business days, unless changed by the WithWeekend option
+const defaultWeekend = 1<<time.Saturday | 1<<time.Sunday
+
+// WithWeekend sets the days of the week which aren't business days for bday expressions. The default weekend is
+// Saturday and Sunday. Calling WithWeekend with no days makes every day of the week a business day.
+//
+// WithWeekend panics if any of the days isn't between time.Sunday and time.Saturday.
+func WithWeekend(days ...time.Weekday) Option {
+	var weekend uint8
+	for _, day := range days {
+		if day < time.Sunday || day > time.Saturday {
+			panic("schyntax: WithWeekend called with an invalid day of the week.")
+		}
+
+		weekend |= 1 << uint(day)
+	}
+
+	return func(o *options) {
+		o.weekend = weekend
+	}
+}
+
+// WithHolidays sets a calendar of holidays, which aren't business days for bday expressions, such as
+// USFederalHolidays. By default, there are no holidays.
+//
+// WithHolidays panics if calendar is nil.
+func WithHolidays(calendar Calendar) Option {
+	if calendar == nil {
+		panic("schyntax: WithHolidays called with a nil calendar.")
+	}
+
+	return func(o *options) {
+		o.holidays = calendar
+	}
+}
+
+// withBusinessWeek sets both the weekend and the holidays, including no holidays, for schedules created from others.
+func withBusinessWeek(weekend uint8, holidays Calendar) Option {
+	return func(o *options) {
+		o.weekend = weekend
+		o.holidays = holidays
+	}
+}
+
+// usesBusinessDays returns true if any of the schedule's groups has a bday expression.
+func (s *scheduleImpl) usesBusinessDays() bool {
+	for _, group := range s.ir.Groups {
+		if group.HasBusinessDays() || group.HasBusinessDaysExcluded() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isBusinessDay returns true if the date is neither on the weekend nor a holiday.
+func (s *scheduleImpl) isBusinessDay(year, month, day int) bool {
+	weekday := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Weekday()
+	if s.weekend&(1<<uint(weekday)) != 0 {
+		return false
+	}
+
+	return s.holidays == nil || !s.holidays.Contains(year, time.Month(month), day)
+}
+
+// countBusinessDays returns the number of business days from the first day of the month to the last, inclusive.
+func (s *scheduleImpl) countBusinessDays(year, month, first, last int) int {
+	count := 0
+	for day := first; day <= last; day++ {
+		if s.isBusinessDay(year, month, day) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// inBusinessDayRule returns true if the date, which must be a business day, is in any of the ranges.
+func (s *scheduleImpl) inBusinessDayRule(ranges []*internals.IrIntegerRange, year, month, dayOfMonth int) bool {
+	for _, r := range ranges {
+		if s.inBusinessDayRange(r, year, month, dayOfMonth) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inBusinessDayRange returns true if the date, which must be a business day, is in the range. Without a from day,
+// business days are numbered through the whole month, and negative values count back from the end of the month, the
+// same way negative days of the month do. With a from day, positive values count forward from the from day, and
+// negative values count back from the day before it.
+func (s *scheduleImpl) inBusinessDayRange(r *internals.IrIntegerRange, year, month, dayOfMonth int) bool {
+	daysInMonth := internals.DaysInMonth(year, month)
+
+	// the business days which are numbered are the ones from first to last, and dayOfMonth is the nth of them
+	first, last := 1, daysInMonth
+	if r.From != 0 {
+		from := min(r.From, daysInMonth+1)
+		if r.Start < 0 {
+			last = from - 1
+		} else {
+			first = from
+		}
+	}
+
+	if dayOfMonth < first || dayOfMonth > last {
+		return false
+	}
+
+	nth := s.countBusinessDays(year, month, first, dayOfMonth)
+	count := nth + s.countBusinessDays(year, month, dayOfMonth+1, last)
+
+	if r.Start < 0 || (r.IsRange && r.End < 0) {
+		// convert negative values to their positive equivalent by counting back from the last business day
+		revisedStart := r.Start
+		if revisedStart < 0 {
+			revisedStart = count + revisedStart + 1
+		}
+
+		revisedEnd := r.End
+		if revisedEnd < 0 {
+			revisedEnd = count + revisedEnd + 1
+		}
+
+		r = r.CloneWithRevisedRange(revisedStart, revisedEnd)
+	}
+
+	return r.Contains(nth, count)
+}
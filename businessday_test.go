@@ -0,0 +1,80 @@
+package schyntax
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusinessDays(t *testing.T) {
+	checks := []*check{
+		newCheck(t, "bday(3) h(9)", "2025-01-10T00:00:00Z", "2025-01-03T09:00:00Z", "2025-02-05T09:00:00Z"),
+		newCheck(t, "bday(-1)", "2025-02-15T00:00:00Z", "2025-01-31T00:00:00Z", "2025-02-28T00:00:00Z"),
+		newCheck(t, "bday(1) h(9)", "2025-02-15T00:00:00Z", "2025-02-03T09:00:00Z", "2025-03-03T09:00:00Z"),
+		// June 15, 2025 is a Sunday
+		newCheck(t, "bday(-1, from 15)", "2025-06-01T00:00:00Z", "2025-05-14T00:00:00Z", "2025-06-13T00:00:00Z"),
+		newCheck(t, "BusinessDays(1, FROM 15)", "2025-06-01T00:00:00Z", "2025-05-15T00:00:00Z", "2025-06-16T00:00:00Z"),
+		newCheck(t, "bday(*) h(9)", "2025-01-10T12:00:00Z", "2025-01-10T09:00:00Z", "2025-01-13T09:00:00Z"),
+		newCheck(t, "bday(1..-1%5)", "2025-01-10T12:00:00Z", "2025-01-08T00:00:00Z", "2025-01-15T00:00:00Z"),
+		newCheck(t, "bday(!1..2) dom(1..7)", "2025-01-06T12:00:00Z", "2025-01-06T00:00:00Z", "2025-01-07T00:00:00Z"),
+		newParseErrorCheck("bday(0)", 5),
+		newParseErrorCheck("bday(-32)", 5),
+		newParseErrorCheck("bday(from 15)", 5),
+		newParseErrorCheck("bday(1, from 15, 2)", 8),
+		newParseErrorCheck("bday(1, from 32)", 13),
+		newParseErrorCheck("bday(1, from 15..20)", 8),
+		newParseErrorCheck("bday(1..-1, from 15)", 5),
+		newParseErrorCheck("bday(1#2)", 6),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+
+	// January 1, 2025 is a Wednesday
+	tests := []struct {
+		options []Option
+		next    string
+	}{
+		{nil, "2025-01-03T00:00:00Z"},
+		{[]Option{WithWeekend(time.Friday, time.Saturday)}, "2025-01-05T00:00:00Z"},
+		{[]Option{WithWeekend()}, "2025-01-03T00:00:00Z"},
+		{[]Option{WithHolidays(USFederalHolidays)}, "2025-01-06T00:00:00Z"},
+	}
+
+	for _, test := range tests {
+		sch, err := New("bday(3)", test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		next, err := sch.NextAfter(parseTestTime(t, "2024-12-31T12:00:00Z"))
+		if err != nil || !next.Equal(parseTestTime(t, test.next)) {
+			t.Errorf("Expected %s, got %s (%v)", test.next, next, err)
+		}
+	}
+
+	if text := NewBuilder().Group(NthBusinessDays(Value(3)), NthBusinessDaysFrom(15, Value(-1))).String(); text != "bday(3) bday(-1, from 15)" {
+		t.Errorf("Unexpected builder text %q", text)
+	}
+
+	if formatted, err := Format("BusinessDays(1..3, FROM 15)"); err != nil || formatted != "bday(1..3, from 15)" {
+		t.Errorf("Unexpected format %q (%v)", formatted, err)
+	}
+}
+
+func TestBusinessDayOptionErrors(t *testing.T) {
+	for name, option := range map[string]func(){
+		"WithWeekend":  func() { WithWeekend(time.Weekday(7)) },
+		"WithHolidays": func() { WithHolidays(nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected %s to panic", name)
+				}
+			}()
+
+			option()
+		}()
+	}
+}
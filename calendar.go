@@ -0,0 +1,212 @@
+package schyntax
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// Calendar is a set of dates which schedules can refer to by name with the cal expression, such as
+// "cal(business-days, !us-federal)". Calendars are registered with the WithCalendar option.
+type Calendar interface {
+	// Contains returns true if the date is in the calendar.
+	Contains(year int, month time.Month, day int) bool
+}
+
+// CalendarFunc is a function which implements Calendar.
+type CalendarFunc func(year int, month time.Month, day int) bool
+
+func (f CalendarFunc) Contains(year int, month time.Month, day int) bool {
+	return f(year, month, day)
+}
+
+// DateSet is a calendar of explicit dates. Dates whose Year is zero are in the calendar every year.
+type DateSet map[Date]bool
+
+// NewDateSet returns a calendar which contains dates.
+func NewDateSet(dates ...Date) DateSet {
+	set := make(DateSet, len(dates))
+	for _, date := range dates {
+		set[date] = true
+	}
+
+	return set
+}
+
+func (s DateSet) Contains(year int, month time.Month, day int) bool {
+	return s[Date{year, month, day}] || s[Date{0, month, day}]
+}
+
+// The built-in calendars, which are computed from rules, so they cover every year. Calendars registered with
+// WithCalendar take precedence over built-in calendars with the same name.
+var (
+	// USFederalHolidays contains the days on which United States federal holidays are observed. A holiday which falls
+	// on a Saturday is observed on the Friday before, and one which falls on a Sunday is observed on the Monday after.
+	// Martin Luther King Jr. Day is included from 1986, and Juneteenth from 2021. Its name is "us-federal".
+	USFederalHolidays Calendar = CalendarFunc(isUSFederalHoliday)
+	// BusinessDays contains Monday through Friday, except for USFederalHolidays. Its name is "business-days".
+	BusinessDays Calendar = CalendarFunc(isBusinessDay)
+	// EasterSunday contains the date of Easter in the Gregorian calendar. Its name is "easter".
+	EasterSunday Calendar = CalendarFunc(isEaster)
+)
+
+var builtInCalendars = map[string]Calendar{
+	"us-federal":    USFederalHolidays,
+	"business-days": BusinessDays,
+	"easter":        EasterSunday,
+}
+
+var calendarNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// WithCalendar registers a calendar for the schedule being created, so that it can be referred to as cal(name). Names
+// are case-insensitive. Each call to New has its own set of calendars, so different schedules can use the same name
+// for different calendars.
+//
+// WithCalendar panics if calendar is nil, or if name doesn't start with a letter followed by letters, digits, hyphens
+// and underscores.
+func WithCalendar(name string, calendar Calendar) Option {
+	if calendar == nil {
+		panic("schyntax: WithCalendar called with a nil calendar.")
+	}
+
+	if !calendarNamePattern.MatchString(name) {
+		panic(`schyntax: "` + name + `" is not a valid calendar name.`)
+	}
+
+	return func(o *options) {
+		if o.calendars == nil {
+			o.calendars = make(map[string]Calendar)
+		}
+
+		o.calendars[strings.ToLower(name)] = calendar
+	}
+}
+
+// lookupCalendar returns the calendar registered with name, or the built-in calendar with that name.
+func (o *options) lookupCalendar(name string) (Calendar, bool) {
+	if calendar, ok := o.calendars[name]; ok {
+		return calendar, true
+	}
+
+	calendar, ok := builtInCalendars[name]
+	return calendar, ok
+}
+
+func (o *options) isCalendar(name string) bool {
+	_, ok := o.lookupCalendar(name)
+	return ok
+}
+
+// calendarsOf returns the calendars which are referred to by the program's groups, or nil if there aren't any.
+func calendarsOf(opts *options, ir *internals.IrProgram) map[string]Calendar {
+	var calendars map[string]Calendar
+	for _, group := range ir.Groups {
+		for _, name := range append(append([]string(nil), group.Calendars...), group.CalendarsExcluded...) {
+			if calendars == nil {
+				calendars = make(map[string]Calendar)
+			}
+
+			calendars[name], _ = opts.lookupCalendar(name)
+		}
+	}
+
+	return calendars
+}
+
+// inCalendars returns true if the date is in any of the named calendars.
+func (s *scheduleImpl) inCalendars(names []string, year, month, day int) bool {
+	for _, name := range names {
+		if s.calendars[name].Contains(year, time.Month(month), day) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Easter returns the date of Easter Sunday in the Gregorian calendar, at midnight UTC.
+func Easter(year int) time.Time {
+	// anonymous Gregorian algorithm
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := (19*a + b - b/4 - (b-(b+8)/25+1)/3 + 15) % 30
+	e := (32 + 2*(b%4) + 2*(c/4) - d - c%4) % 7
+	f := d + e - 7*((a+11*d+22*e)/451) + 114
+
+	return time.Date(year, time.Month(f/31), f%31+1, 0, 0, 0, 0, time.UTC)
+}
+
+func isEaster(year int, month time.Month, day int) bool {
+	easter := Easter(year)
+	return easter.Month() == month && easter.Day() == day
+}
+
+func isBusinessDay(year int, month time.Month, day int) bool {
+	weekday := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday && !isUSFederalHoliday(year, month, day)
+}
+
+func isUSFederalHoliday(year int, month time.Month, day int) bool {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	// New Year's Day can be observed on December 31 of the previous year
+	for _, holiday := range append(usFederalHolidays(year), usFederalHolidays(year + 1)[0]) {
+		if holiday.Equal(date) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// usFederalHolidays returns the observed dates of the federal holidays of year, starting with New Year's Day.
+func usFederalHolidays(year int) []time.Time {
+	fixed := func(month time.Month, day int) time.Time {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		switch date.Weekday() {
+		case time.Saturday:
+			return date.AddDate(0, 0, -1)
+		case time.Sunday:
+			return date.AddDate(0, 0, 1)
+		}
+
+		return date
+	}
+
+	// the nth weekday of the month, where a negative n counts back from the end of the month
+	nth := func(month time.Month, weekday time.Weekday, n int) time.Time {
+		if n < 0 {
+			last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+			return last.AddDate(0, 0, -((int(last.Weekday())-int(weekday)+7)%7 + 7*(-n-1)))
+		}
+
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+	}
+
+	holidays := []time.Time{fixed(time.January, 1)}
+	if year >= 1986 {
+		holidays = append(holidays, nth(time.January, time.Monday, 3))
+	}
+
+	holidays = append(holidays,
+		nth(time.February, time.Monday, 3),
+		nth(time.May, time.Monday, -1),
+	)
+
+	if year >= 2021 {
+		holidays = append(holidays, fixed(time.June, 19))
+	}
+
+	return append(holidays,
+		fixed(time.July, 4),
+		nth(time.September, time.Monday, 1),
+		nth(time.October, time.Monday, 2),
+		fixed(time.November, 11),
+		nth(time.November, time.Thursday, 4),
+		fixed(time.December, 25),
+	)
+}
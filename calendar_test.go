@@ -0,0 +1,120 @@
+package schyntax
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuiltInCalendars(t *testing.T) {
+	tests := []struct {
+		calendar Calendar
+		date     string
+		contains bool
+	}{
+		{USFederalHolidays, "2025-01-20", true},  // Martin Luther King Jr. Day
+		{USFederalHolidays, "1985-01-21", false}, // before Martin Luther King Jr. Day was observed
+		{USFederalHolidays, "2025-05-26", true},  // Memorial Day
+		{USFederalHolidays, "2025-06-19", true},  // Juneteenth
+		{USFederalHolidays, "2020-06-19", false},
+		{USFederalHolidays, "2026-07-03", true}, // July 4 is a Saturday
+		{USFederalHolidays, "2026-07-04", false},
+		{USFederalHolidays, "2021-12-31", true}, // New Year's Day 2022 is a Saturday
+		{USFederalHolidays, "2023-01-02", true}, // New Year's Day 2023 is a Sunday
+		{USFederalHolidays, "2025-11-27", true}, // Thanksgiving
+		{USFederalHolidays, "2025-11-20", false},
+		{BusinessDays, "2025-11-26", true},
+		{BusinessDays, "2025-11-27", false},
+		{BusinessDays, "2025-11-29", false},
+		{EasterSunday, "2025-04-20", true},
+		{EasterSunday, "2024-03-31", true},
+		{EasterSunday, "2024-04-20", false},
+	}
+
+	for _, test := range tests {
+		date := parseTestTime(t, test.date+"T00:00:00Z")
+		if contains := test.calendar.Contains(date.Year(), date.Month(), date.Day()); contains != test.contains {
+			t.Errorf("%s: expected %t, got %t", test.date, test.contains, contains)
+		}
+	}
+}
+
+func TestCalendars(t *testing.T) {
+	sch, err := New("h(9) cal(business-days)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Thanksgiving and the weekend are skipped
+	events := sch.ListOccurrences(parseTestTime(t, "2025-11-26T10:00:00Z"), parseTestTime(t, "2025-12-02T00:00:00Z"), 0)
+	if len(events) != 2 || !events[0].Equal(parseTestTime(t, "2025-11-28T09:00:00Z")) || !events[1].Equal(parseTestTime(t, "2025-12-01T09:00:00Z")) {
+		t.Errorf("Unexpected events %v", events)
+	}
+
+	sch, err = New("h(0) dow(mon) CAL(!US-Federal)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, _ := sch.NextAfter(parseTestTime(t, "2025-05-20T00:00:00Z"))
+	if !next.Equal(parseTestTime(t, "2025-06-02T00:00:00Z")) {
+		t.Errorf("Expected Memorial Day to be skipped, got %s", next)
+	}
+
+	// calendars are registered per schedule, and shadow the built-in calendars
+	closures := NewDateSet(Date{2025, time.March, 3}, Date{0, time.December, 26})
+	sch, err = New("h(9) dow(mon..fri) cal(!closed)", WithCalendar("Closed", closures))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events = sch.ListOccurrences(parseTestTime(t, "2025-03-03T00:00:00Z"), parseTestTime(t, "2025-03-05T00:00:00Z"), 0)
+	if len(events) != 1 || !events[0].Equal(parseTestTime(t, "2025-03-04T09:00:00Z")) {
+		t.Errorf("Unexpected events %v", events)
+	}
+
+	if _, err := New("h(9) cal(closed)"); err == nil {
+		t.Error("Expected a calendar registered with another schedule to be unknown")
+	}
+
+	everyDay := CalendarFunc(func(year int, month time.Month, day int) bool { return true })
+	sch, _ = New("h(9) cal(easter)", WithCalendar("easter", everyDay))
+	if next, _ := sch.NextAfter(parseTestTime(t, "2025-06-15T10:00:00Z")); !next.Equal(parseTestTime(t, "2025-06-16T09:00:00Z")) {
+		t.Errorf("Expected the registered calendar to be used, got %s", next)
+	}
+}
+
+func TestCalendarErrors(t *testing.T) {
+	checks := []*check{
+		newParseErrorCheck("cal(holidays)", 4),
+		newParseErrorCheck("h(9) cal(us-federal, nope)", 21),
+		newParseErrorCheck("cal(*)", 4),
+		newParseErrorCheck("cal(easter..us-federal)", 10),
+		newParseErrorCheck("cal(1)", 4),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected WithCalendar to panic with an invalid name")
+			}
+		}()
+
+		WithCalendar("1st", EasterSunday)
+	}()
+}
+
+func TestCalendarText(t *testing.T) {
+	formatted, err := Format("calendar(Business-Days) h(9)", SortExpressions())
+	if err != nil || formatted != "h(9) cal(business-days)" {
+		t.Errorf("Unexpected format %q (%v)", formatted, err)
+	}
+
+	text := NewBuilder().Group(Hours(Value(9)), Calendars(Value[CalendarName]("business-days"), Value[CalendarName]("us-federal").Not())).String()
+	if text != "h(9) cal(business-days, !us-federal)" {
+		t.Errorf("Unexpected builder text %q", text)
+	}
+}
@@ -0,0 +1,391 @@
+package schyntax
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// CronConversion is the result of converting a cron expression into a schyntax schedule.
+type CronConversion struct {
+	// Cron is the original cron expression.
+	Cron string
+	// Schedule is the equivalent schyntax schedule.
+	Schedule string
+	// Notes describe any places where cron's semantics don't map directly onto a single schyntax construct, and how
+	// they were converted.
+	Notes []string
+}
+
+// CronError is returned when a cron expression can't be parsed, or uses a feature which has no schyntax equivalent.
+type CronError struct {
+	message string
+	input   string
+	index   int
+}
+
+var _ SchyntaxError = &CronError{}
+
+func newCronError(msg string, input string, index int) *CronError {
+	return &CronError{msg + " (at index " + strconv.Itoa(index) + " of cron expression \"" + input + "\")", input, index}
+}
+
+func (e *CronError) Error() string {
+	return e.message
+}
+
+func (e *CronError) Input() string {
+	return e.input
+}
+
+func (e *CronError) Index() int {
+	return e.index
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronFieldType int8
+
+const (
+	cronSeconds cronFieldType = iota
+	cronMinutes
+	cronHours
+	cronDaysOfMonth
+	cronMonths
+	cronDaysOfWeek
+)
+
+var cronExpressionTypes = map[cronFieldType]internals.ExpressionType{
+	cronSeconds:     internals.ExpressionTypeSeconds,
+	cronMinutes:     internals.ExpressionTypeMinutes,
+	cronHours:       internals.ExpressionTypeHours,
+	cronDaysOfMonth: internals.ExpressionTypeDaysOfMonth,
+	cronMonths:      internals.ExpressionTypeMonths,
+	cronDaysOfWeek:  internals.ExpressionTypeDaysOfWeek,
+}
+
+type cronField struct {
+	fieldType cronFieldType
+	text      string
+	index     int // index of the field in the original expression
+}
+
+// isRestricted returns false if the field starts with a wildcard. This follows Vixie cron, which only treats the days of
+// month and week as alternatives when neither field starts with "*".
+func (f *cronField) isRestricted() bool {
+	return !strings.HasPrefix(f.text, "*") && !strings.HasPrefix(f.text, "?")
+}
+
+// NewFromCron converts a cron expression into a schyntax schedule. See ConvertCron.
+func NewFromCron(cron string, options ...Option) (Schedule, error) {
+	conversion, err := ConvertCron(cron)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(conversion.Schedule, options...)
+}
+
+// ConvertCron converts a standard five-field cron expression (minute, hour, day of month, month, day of week), or a
+// six-field expression with a leading seconds field, into equivalent schyntax source text. Lists, ranges, steps,
+// month and day names, and the @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly macros are
+// supported, as well as "L" in the day of month field, and "nL" and "n#k" in the day of week field.
+//
+// When both the day of month and day of week fields are restricted, cron matches days which satisfy either field.
+// Schyntax expressions within a group must all match, so the conversion uses two groups and adds a note explaining why.
+func ConvertCron(cron string) (*CronConversion, error) {
+	conversion := &CronConversion{Cron: cron}
+
+	fields, err := splitCronFields(cron)
+	if err != nil {
+		return nil, err
+	}
+
+	var dayOfMonth, dayOfWeek *cronField
+	var common, dayOfMonthExp, dayOfWeekExp string
+
+	for _, f := range fields {
+		exp, err := convertCronField(cron, f)
+		if err != nil {
+			return nil, err
+		}
+
+		switch f.fieldType {
+		case cronDaysOfMonth:
+			dayOfMonth = f
+			dayOfMonthExp = exp
+		case cronDaysOfWeek:
+			dayOfWeek = f
+			dayOfWeekExp = exp
+		default:
+			if exp != "" {
+				if common != "" {
+					common += " "
+				}
+
+				common += exp
+			}
+		}
+	}
+
+	if dayOfMonth.isRestricted() && dayOfWeek.isRestricted() {
+		conversion.Schedule = "{" + common + " " + dayOfMonthExp + "}, {" + common + " " + dayOfWeekExp + "}"
+		conversion.Notes = append(conversion.Notes, `Cron runs when either the day of month ("`+dayOfMonth.text+`") or the day of week ("`+
+			dayOfWeek.text+`") matches, so each is placed in its own group.`)
+	} else {
+		conversion.Schedule = common
+		for _, exp := range []string{dayOfMonthExp, dayOfWeekExp} {
+			if exp != "" {
+				conversion.Schedule += " " + exp
+			}
+		}
+
+		if dayOfMonthExp != "" && dayOfWeekExp != "" {
+			conversion.Notes = append(conversion.Notes, `Because one of the day of month ("`+dayOfMonth.text+`") and day of week ("`+
+				dayOfWeek.text+`") fields starts with "*", both must match, following Vixie cron.`)
+		}
+	}
+
+	if strings.Contains(dayOfMonth.text, "?") || strings.Contains(dayOfWeek.text, "?") {
+		conversion.Notes = append(conversion.Notes, `"?" was treated the same as "*".`)
+	}
+
+	return conversion, nil
+}
+
+func splitCronFields(cron string) ([]*cronField, error) {
+	trimmed := strings.TrimSpace(cron)
+	if strings.HasPrefix(trimmed, "@") {
+		index := strings.Index(cron, "@")
+		expanded, ok := cronMacros[strings.ToLower(trimmed)]
+		if !ok {
+			return nil, newCronError(`Unsupported cron macro "`+trimmed+`".`, cron, index)
+		}
+
+		// every field of the expansion points back at the macro
+		fields := make([]*cronField, 0, 5)
+		for i, text := range strings.Fields(expanded) {
+			fields = append(fields, &cronField{cronFieldType(i + 1), text, index})
+		}
+
+		return fields, nil
+	}
+
+	var fields []*cronField
+	for i := 0; i < len(cron); {
+		if cron[i] == ' ' || cron[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(cron) && cron[i] != ' ' && cron[i] != '\t' {
+			i++
+		}
+
+		fields = append(fields, &cronField{text: cron[start:i], index: start})
+	}
+
+	var first cronFieldType
+	switch len(fields) {
+	case 5:
+		first = cronMinutes
+	case 6:
+		first = cronSeconds
+	default:
+		return nil, newCronError("Cron expressions must have five or six fields, but found "+strconv.Itoa(len(fields))+".", cron, 0)
+	}
+
+	for i, f := range fields {
+		f.fieldType = first + cronFieldType(i)
+	}
+
+	return fields, nil
+}
+
+// convertCronField returns the schyntax expression for a cron field, or an empty string if the expression isn't needed.
+func convertCronField(cron string, f *cronField) (string, error) {
+	if f.fieldType == cronSeconds && f.text == "0" {
+		return "", nil // implied by the minutes expression
+	}
+
+	name := canonicalExpressionNames[cronExpressionTypes[f.fieldType]]
+
+	if f.text == "*" || (f.text == "?" && (f.fieldType == cronDaysOfMonth || f.fieldType == cronDaysOfWeek)) {
+		if f.fieldType == cronSeconds || f.fieldType == cronMinutes {
+			return name + "(*)", nil
+		}
+
+		return "", nil
+	}
+
+	var args []string
+	index := f.index
+	for _, item := range strings.Split(f.text, ",") {
+		arg, err := convertCronItem(cron, f.fieldType, item, index)
+		if err != nil {
+			return "", err
+		}
+
+		args = append(args, arg)
+		index += len(item) + 1
+	}
+
+	return name + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+func convertCronItem(cron string, fieldType cronFieldType, item string, index int) (string, error) {
+	if item == "" {
+		return "", newCronError("Empty list item.", cron, index)
+	}
+
+	upper := strings.ToUpper(item)
+	if strings.Contains(upper, "W") && fieldType == cronDaysOfMonth {
+		return "", newCronError(`The "W" (nearest weekday) operator has no schyntax equivalent.`, cron, index)
+	}
+
+	if fieldType == cronDaysOfMonth && upper == "L" {
+		return "-1", nil
+	}
+
+	if fieldType == cronDaysOfWeek {
+		if strings.HasSuffix(upper, "L") && len(upper) > 1 {
+			day, err := parseCronValue(cron, fieldType, item[:len(item)-1], index)
+			if err != nil {
+				return "", err
+			}
+
+			return dayLiterals[day%7] + "#-1", nil
+		}
+
+		if hash := strings.Index(item, "#"); hash != -1 {
+			day, err := parseCronValue(cron, fieldType, item[:hash], index)
+			if err != nil {
+				return "", err
+			}
+
+			nth, err := strconv.Atoi(item[hash+1:])
+			if err != nil || nth < 1 || nth > 5 {
+				return "", newCronError(`"`+item[hash+1:]+`" is not a valid occurrence. Must be between 1 and 5.`, cron, index+hash+1)
+			}
+
+			return dayLiterals[day%7] + "#" + strconv.Itoa(nth), nil
+		}
+	}
+
+	rangePart := item
+	step := ""
+	if slash := strings.Index(item, "/"); slash != -1 {
+		rangePart = item[:slash]
+		step = item[slash+1:]
+		if n, err := strconv.Atoi(step); err != nil || n < 1 {
+			return "", newCronError(`"`+step+`" is not a valid step.`, cron, index+slash+1)
+		}
+	}
+
+	if rangePart == "?" && fieldType != cronDaysOfMonth && fieldType != cronDaysOfWeek {
+		return "", newCronError(`"?" is only allowed in the day of month and day of week fields.`, cron, index)
+	}
+
+	var arg string
+	if rangePart == "*" || rangePart == "?" {
+		arg = "*"
+	} else if dash := strings.Index(rangePart, "-"); dash != -1 {
+		start, err := parseCronValue(cron, fieldType, rangePart[:dash], index)
+		if err != nil {
+			return "", err
+		}
+
+		end, err := parseCronValue(cron, fieldType, rangePart[dash+1:], index+dash+1)
+		if err != nil {
+			return "", err
+		}
+
+		if end < start && fieldType != cronDaysOfWeek {
+			return "", newCronError(`Range "`+rangePart+`" ends before it starts.`, cron, index)
+		}
+
+		if fieldType == cronDaysOfWeek && start == 0 && end == 7 {
+			// both ends are Sunday, but the range covers the whole week
+			arg = "*"
+		} else {
+			arg = formatCronValue(fieldType, start) + ".." + formatCronValue(fieldType, end)
+		}
+	} else {
+		value, err := parseCronValue(cron, fieldType, rangePart, index)
+		if err != nil {
+			return "", err
+		}
+
+		arg = formatCronValue(fieldType, value)
+	}
+
+	if step != "" {
+		arg += "%" + step
+	}
+
+	return arg, nil
+}
+
+func parseCronValue(cron string, fieldType cronFieldType, text string, index int) (int, error) {
+	lower := strings.ToLower(text)
+	switch fieldType {
+	case cronMonths:
+		for i, name := range monthLiterals {
+			if lower == name {
+				return i + 1, nil
+			}
+		}
+	case cronDaysOfWeek:
+		for i, name := range dayLiterals {
+			if lower == name {
+				return i, nil
+			}
+		}
+	}
+
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, newCronError(`"`+text+`" is not a valid value.`, cron, index)
+	}
+
+	var min, max int
+	switch fieldType {
+	case cronSeconds, cronMinutes:
+		min, max = 0, 59
+	case cronHours:
+		min, max = 0, 23
+	case cronDaysOfMonth:
+		min, max = 1, 31
+	case cronMonths:
+		min, max = 1, 12
+	case cronDaysOfWeek:
+		min, max = 0, 7
+	}
+
+	if value < min || value > max {
+		return 0, newCronError(`"`+text+`" is out of range. Must be between `+strconv.Itoa(min)+" and "+strconv.Itoa(max)+".", cron, index)
+	}
+
+	return value, nil
+}
+
+func formatCronValue(fieldType cronFieldType, value int) string {
+	switch fieldType {
+	case cronMonths:
+		return monthLiterals[value-1]
+	case cronDaysOfWeek:
+		return dayLiterals[value%7] // cron allows both 0 and 7 for Sunday
+	}
+
+	return strconv.Itoa(value)
+}
@@ -0,0 +1,248 @@
+package schyntax
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertCron(t *testing.T) {
+	tests := []struct {
+		cron     string
+		schedule string
+		notes    int
+	}{
+		{"* * * * *", "min(*)", 0},
+		{"*/5 * * * *", "min(*%5)", 0},
+		{"0 9 * * 1-5", "min(0) h(9) dow(mon..fri)", 0},
+		{"0,30 8-18/2 * * *", "min(0, 30) h(8..18%2)", 0},
+		{"15 10 * JAN,jul Sun", "min(15) h(10) months(jan, jul) dow(sun)", 0},
+		{"0 0 1 3-5 *", "min(0) h(0) months(mar..may) dom(1)", 0},
+		{"0 12 L * *", "min(0) h(12) dom(-1)", 0},
+		{"0 0 * * 5-7", "min(0) h(0) dow(fri..sun)", 0},
+		{"0 0 * * 7", "min(0) h(0) dow(sun)", 0},
+		{"0 9 * * 0-7", "min(0) h(9) dow(*)", 0},
+		{"0 9 * * 0-7/2", "min(0) h(9) dow(*%2)", 0},
+		{"0 9 * * 1-7", "min(0) h(9) dow(mon..sun)", 0},
+		{"0 0 * * 5L", "min(0) h(0) dow(fri#-1)", 0},
+		{"0 0 * * 1#2", "min(0) h(0) dow(mon#2)", 0},
+		{"0 0 1,15 * 1", "{min(0) h(0) dom(1, 15)}, {min(0) h(0) dow(mon)}", 1},
+		{"0 0 */2 * 1", "min(0) h(0) dom(*%2) dow(mon)", 1},
+		{"0 0 ? * 1", "min(0) h(0) dow(mon)", 1},
+		{"30 */10 * * * *", "s(30) min(*%10)", 0},
+		{"0 0 12 * * *", "min(0) h(12)", 0},
+		{"@daily", "min(0) h(0)", 0},
+		{"@WEEKLY", "min(0) h(0) dow(sun)", 0},
+		{"@yearly", "min(0) h(0) months(jan) dom(1)", 0},
+		{"@hourly", "min(0)", 0},
+	}
+
+	for _, test := range tests {
+		conversion, err := ConvertCron(test.cron)
+		if err != nil {
+			t.Errorf("%q: %s", test.cron, err)
+			continue
+		}
+
+		if conversion.Schedule != test.schedule {
+			t.Errorf("%q. Expected: %q, Actual: %q", test.cron, test.schedule, conversion.Schedule)
+		}
+
+		if len(conversion.Notes) != test.notes {
+			t.Errorf("%q. Expected %d notes, got %v", test.cron, test.notes, conversion.Notes)
+		}
+
+		if _, err := New(conversion.Schedule); err != nil {
+			t.Errorf("%q converted to an invalid schedule: %s", test.cron, err)
+		}
+	}
+}
+
+func TestConvertCronErrors(t *testing.T) {
+	tests := []struct {
+		cron  string
+		index int
+	}{
+		{"* * * *", 0},
+		{"* * * * * * *", 0},
+		{"@reboot", 0},
+		{"60 * * * *", 0},
+		{"0 24 * * *", 2},
+		{"0 0 0 * *", 4},
+		{"0 0 * 13 *", 6},
+		{"0 0 * * 8", 8},
+		{"0 0 15W * *", 4},
+		{"0 0 1,,2 * *", 6},
+		{"0 0 * * mon#6", 12},
+		{"0 5-2 * * *", 2},
+		{"*/0 * * * *", 2},
+		{"? * * * *", 0},
+		{"0 0 * foo *", 6},
+	}
+
+	for _, test := range tests {
+		_, err := ConvertCron(test.cron)
+		if err == nil {
+			t.Errorf("%q: expected an error", test.cron)
+			continue
+		}
+
+		cronErr, ok := err.(*CronError)
+		if !ok {
+			t.Errorf("%q: expected a *CronError, got %T", test.cron, err)
+			continue
+		}
+
+		if cronErr.Index() != test.index {
+			t.Errorf("%q. Expected index %d, got %d: %s", test.cron, test.index, cronErr.Index(), err)
+		}
+	}
+}
+
+func TestNewFromCron(t *testing.T) {
+	// the 1st of the month or any Monday, at 09:30
+	sch, err := NewFromCron("30 9 1 * mon")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := parseTestTime(t, "2025-06-01T00:00:00Z")
+	to := parseTestTime(t, "2025-06-17T00:00:00Z")
+	assertEvents(t, "cron", sch.ListOccurrences(from, to, 0), []string{
+		"2025-06-01T09:30:00Z",
+		"2025-06-02T09:30:00Z",
+		"2025-06-09T09:30:00Z",
+		"2025-06-16T09:30:00Z",
+	})
+
+	sch, err = NewFromCron("0 0 * * 0", InLocation(time.FixedZone("test", 3600)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := sch.NextAfter(parseTestTime(t, "2025-06-02T00:00:00Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := parseTestTime(t, "2025-06-07T23:00:00Z"); !next.Equal(expected) {
+		t.Errorf("Expected: %s, Actual: %s", expected, next)
+	}
+
+	if _, err := NewFromCron("0 0 * *"); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestCronExport(t *testing.T) {
+	tests := []struct {
+		schedule string
+		lines    []string
+	}{
+		{"min(*%5)", []string{"*/5 * * * *"}},
+		{"dow(mon..fri) h(9..<17) min(0, 30)", []string{"0,30 9-16 * * 1-5"}},
+		{"h(8..18%2) min(15)", []string{"15 8-18/2 * * *"}},
+		{"hours(!12) min(0)", []string{"0 0-11,13-23 * * *"}},
+		{"dow(!sat, !sun) h(6)", []string{"0 6 * * 1-5"}},
+		{"dom(1, 15) months(jan..mar) h(0)", []string{"0 0 1,15 1-3 *"}},
+		{"dom(1..30) h(0)", []string{"0 0 1-30 * *"}},
+		{"dates(1/29..3/31) h(0)", []string{"0 0 29-31 1 *", "0 0 * 2,3 *"}},
+		{"dates(12/24..12/26, !12/25) h(0)", []string{"0 0 24,26 12 *"}},
+		{"{dow(mon) h(9)}, {dom(1) h(9)}, {dow(mon) h(9)}", []string{"0 9 * * 1", "0 9 1 * *"}},
+	}
+
+	for _, test := range tests {
+		sch, err := New(test.schedule)
+		if err != nil {
+			t.Errorf("%q: %s", test.schedule, err)
+			continue
+		}
+
+		lines, err := sch.Cron()
+		if err != nil {
+			t.Errorf("%q: %s", test.schedule, err)
+			continue
+		}
+
+		if strings.Join(lines, "\n") != strings.Join(test.lines, "\n") {
+			t.Errorf("%q. Expected: %q, Actual: %q", test.schedule, test.lines, lines)
+		}
+	}
+}
+
+func TestCronExportErrors(t *testing.T) {
+	tests := []struct {
+		schedule  string
+		construct CronConstruct
+		group     int
+	}{
+		{"s(*%10)", CronConstructSeconds, 0},
+		{"doy(100) h(0)", CronConstructDaysOfYear, 0},
+		{"dom(-1) h(0)", CronConstructNegativeDayOfMonth, 0},
+		{"dom(!-1) h(0)", CronConstructNegativeDayOfMonth, 0},
+		{"{h(0)}, {dow(fri#-1) h(0)}", CronConstructNthDayOfWeek, 1},
+		{"dates(2025/1/1) h(0)", CronConstructDatesWithYears, 0},
+		{"dates(2/20..<3/20%3) h(0)", CronConstructLeapYearInterval, 0},
+		{"dom(1) dow(mon) h(0)", CronConstructDaysOfWeekAndDays, 0},
+		{"ms(500)", CronConstructMilliseconds, 0},
+		{"{h(0)}, {h(0) jitter(30s)}", CronConstructJitter, 1},
+		{"h(0) cal(!us-federal)", CronConstructCalendars, 0},
+		{"h(0) years(2030)", CronConstructYears, 0},
+		{"h(0) weeks(1)", CronConstructWeeks, 0},
+		{"h(0) every(2d, from 2025/1/1)", CronConstructEvery, 0},
+		{"h(0) bday(1)", CronConstructBusinessDays, 0},
+	}
+
+	for _, test := range tests {
+		sch, err := New(test.schedule)
+		if err != nil {
+			t.Errorf("%q: %s", test.schedule, err)
+			continue
+		}
+
+		_, err = sch.Cron()
+		exportErr, ok := err.(*CronExportError)
+		if !ok {
+			t.Errorf("%q: expected a *CronExportError, got %v", test.schedule, err)
+			continue
+		}
+
+		if exportErr.Construct() != test.construct || exportErr.GroupIndex() != test.group {
+			t.Errorf("%q. Expected %q in group %d, got: %s", test.schedule, test.construct, test.group, err)
+		}
+	}
+}
+
+func TestCronRoundTrip(t *testing.T) {
+	crons := []string{"*/15 9-17 * * 1-5", "0 0 1,15 * *", "30 2 * jan,jul *", "0 12 * * 0,6"}
+	for _, cron := range crons {
+		sch, err := NewFromCron(cron)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines, err := sch.Cron()
+		if err != nil {
+			t.Errorf("%q: %s", cron, err)
+			continue
+		}
+
+		if len(lines) != 1 {
+			t.Errorf("%q. Expected one line, got %q", cron, lines)
+			continue
+		}
+
+		exported, err := NewFromCron(lines[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		from := parseTestTime(t, "2025-01-01T00:00:00Z")
+		to := from.AddDate(1, 0, 0)
+		expected := sch.ListOccurrences(from, to, 0)
+		actual := exported.ListOccurrences(from, to, 0)
+		if len(expected) != len(actual) {
+			t.Errorf("%q exported as %q. Expected %d events, got %d", cron, lines[0], len(expected), len(actual))
+		}
+	}
+}
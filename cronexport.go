@@ -0,0 +1,325 @@
+package schyntax
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// CronConstruct identifies a schyntax construct which can't be expressed in cron.
+type CronConstruct string
+
+const (
+	CronConstructSeconds            CronConstruct = "seconds other than zero"
+	CronConstructDaysOfYear         CronConstruct = "days of the year"
+	CronConstructNegativeDayOfMonth CronConstruct = "negative days of the month"
+	CronConstructNthDayOfWeek       CronConstruct = "the # operator"
+	CronConstructDatesWithYears     CronConstruct = "dates with years"
+	CronConstructLeapYearInterval   CronConstruct = "intervals which depend on leap years"
+	CronConstructDaysOfWeekAndDays  CronConstruct = "days of the week combined with days of the month or dates"
+	CronConstructMilliseconds       CronConstruct = "milliseconds other than zero"
+	CronConstructJitter             CronConstruct = "jitter"
+	CronConstructCalendars          CronConstruct = "calendars"
+	CronConstructYears              CronConstruct = "years"
+	CronConstructWeeks              CronConstruct = "ISO weeks"
+	CronConstructEvery              CronConstruct = "every expressions"
+	CronConstructBusinessDays       CronConstruct = "business days"
+)
+
+// CronExportError is returned by Schedule.Cron when part of a schedule can't be expressed in cron.
+type CronExportError struct {
+	input      string
+	groupIndex int
+	construct  CronConstruct
+}
+
+func (e *CronExportError) Error() string {
+	return "The schedule \"" + e.input + "\" can't be expressed in cron because it uses " + string(e.construct) +
+		" (in compiled group " + strconv.Itoa(e.groupIndex) + ")."
+}
+
+// Input returns the schedule's original text.
+func (e *CronExportError) Input() string {
+	return e.input
+}
+
+// GroupIndex returns the index of the compiled group which uses the construct. Free-floating expressions are compiled
+// into the first group.
+func (e *CronExportError) GroupIndex() int {
+	return e.groupIndex
+}
+
+// Construct returns the construct which can't be expressed in cron.
+func (e *CronExportError) Construct() CronConstruct {
+	return e.construct
+}
+
+// the years checked when working out which days of each month a group applies to, covering every month length
+var cronExportYears = []int{2001, 2002, 2003, 2004}
+
+// cronExportLine is a set of months which share the same days of the month. Each day is 1 (included), 0 (excluded) or
+// -1 (doesn't exist in any of the months, so it doesn't matter).
+type cronExportLine struct {
+	months uint16
+	days   [32]int8
+}
+
+func (s *scheduleImpl) Cron() ([]string, error) {
+	var lines []string
+	seen := map[string]bool{}
+	for i, group := range s.ir.Groups {
+		groupLines, construct := s.cronLines(group)
+		if construct != "" {
+			return nil, &CronExportError{s.originalText, i, construct}
+		}
+
+		for _, line := range groupLines {
+			if !seen[line] {
+				seen[line] = true
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	return lines, nil
+}
+
+// cronLines returns the cron lines for a group, or the construct which prevents the group from being expressed.
+func (s *scheduleImpl) cronLines(group *internals.IrGroup) ([]string, CronConstruct) {
+	if group.Jitter != 0 {
+		return nil, CronConstructJitter
+	}
+
+	if group.HasPeriods() {
+		return nil, CronConstructEvery
+	}
+
+	if group.HasBusinessDays() || group.HasBusinessDaysExcluded() {
+		return nil, CronConstructBusinessDays
+	}
+
+	if group.HasWeeks() || group.HasWeeksExcluded() {
+		return nil, CronConstructWeeks
+	}
+
+	if group.HasYears() || group.HasYearsExcluded() {
+		return nil, CronConstructYears
+	}
+
+	if group.HasCalendars() || group.HasCalendarsExcluded() {
+		return nil, CronConstructCalendars
+	}
+
+	if group.MillisecondsMask != internals.ZeroMilliseconds {
+		return nil, CronConstructMilliseconds
+	}
+
+	if group.SecondsMask != 1 {
+		return nil, CronConstructSeconds
+	}
+
+	if group.HasDaysOfYear() || group.HasDaysOfYearExcluded() {
+		return nil, CronConstructDaysOfYear
+	}
+
+	if group.HasDaysOfWeekNth {
+		return nil, CronConstructNthDayOfWeek
+	}
+
+	for _, ranges := range [][]*internals.IrIntegerRange{group.DaysOfMonth, group.DaysOfMonthExcluded} {
+		for _, r := range ranges {
+			if r.Start < 0 || (r.IsRange && r.End < 0) {
+				return nil, CronConstructNegativeDayOfMonth
+			}
+		}
+	}
+
+	for _, ranges := range [][]*internals.IrDateRange{group.Dates, group.DatesExcluded} {
+		for _, r := range ranges {
+			if r.DatesHaveYear {
+				return nil, CronConstructDatesWithYears
+			}
+		}
+	}
+
+	if group.MinutesMask == 0 || group.HoursMask == 0 {
+		return nil, "" // the group can never match
+	}
+
+	// Days of the week are checked separately, because cron only combines them with the other day fields when one of
+	// those fields is "*".
+	days := *group
+	days.DaysOfWeek = nil
+	days.DaysOfWeekExcluded = nil
+	days.CompileMasks()
+
+	var exportLines []*cronExportLine
+	for month := 1; month <= 12; month++ {
+		var monthDays [32]int8
+		for day := 1; day <= 31; day++ {
+			monthDays[day] = -1
+			for _, year := range cronExportYears {
+				if day > internals.DaysInMonth(year, month) {
+					continue
+				}
+
+				applicable := int8(0)
+				if s.isApplicableDate(&days, time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)) {
+					applicable = 1
+				}
+
+				if monthDays[day] != -1 && monthDays[day] != applicable {
+					return nil, CronConstructLeapYearInterval
+				}
+
+				monthDays[day] = applicable
+			}
+		}
+
+		addCronExportMonth(&exportLines, month, &monthDays)
+	}
+
+	var daysOfWeek uint64
+	for dow := 1; dow <= 7; dow++ {
+		bit := uint8(1) << uint(dow)
+		if group.DaysOfWeekMask&bit != 0 && group.DaysOfWeekExcludedMask&bit == 0 {
+			daysOfWeek |= 1 << uint(dow-1) // cron's days of week start at zero
+		}
+	}
+
+	if daysOfWeek == 0 {
+		return nil, ""
+	}
+
+	minutes := formatCronField(group.MinutesMask, 0, 59)
+	hours := formatCronField(uint64(group.HoursMask), 0, 23)
+	dow := formatCronField(daysOfWeek, 0, 6)
+
+	var lines []string
+	for _, line := range exportLines {
+		var dom uint64
+		all := true
+		for day := 1; day <= 31; day++ {
+			if line.days[day] == 1 {
+				dom |= 1 << uint(day)
+			} else if line.days[day] == 0 {
+				all = false
+			}
+		}
+
+		dayOfMonth := "*"
+		if !all {
+			dayOfMonth = formatCronField(dom, 1, 31)
+		}
+
+		if dayOfMonth != "*" && dow != "*" {
+			return nil, CronConstructDaysOfWeekAndDays
+		}
+
+		months := formatCronField(uint64(line.months), 1, 12)
+		lines = append(lines, minutes+" "+hours+" "+dayOfMonth+" "+months+" "+dow)
+	}
+
+	return lines, ""
+}
+
+// addCronExportMonth adds a month to the first line whose days are compatible with it, or to a new line.
+func addCronExportMonth(lines *[]*cronExportLine, month int, days *[32]int8) {
+	applicable := false
+	for day := 1; day <= 31; day++ {
+		if days[day] == 1 {
+			applicable = true
+			break
+		}
+	}
+
+	if !applicable {
+		return
+	}
+
+	var target *cronExportLine
+	for _, line := range *lines {
+		compatible := true
+		for day := 1; day <= 31; day++ {
+			if days[day] != -1 && line.days[day] != -1 && days[day] != line.days[day] {
+				compatible = false
+				break
+			}
+		}
+
+		if compatible {
+			target = line
+			break
+		}
+	}
+
+	if target == nil {
+		target = &cronExportLine{}
+		for day := range target.days {
+			target.days[day] = -1
+		}
+
+		*lines = append(*lines, target)
+	}
+
+	target.months |= 1 << uint(month)
+	for day := 1; day <= 31; day++ {
+		if days[day] != -1 {
+			target.days[day] = days[day]
+		}
+	}
+}
+
+// formatCronField renders the values between min and max which are set in mask as a cron field, using "*", "*/n" or
+// "a-b/n" when the values allow it, and a list of values and ranges otherwise.
+func formatCronField(mask uint64, min, max int) string {
+	var values []int
+	for v := min; v <= max; v++ {
+		if mask&(1<<uint(v)) != 0 {
+			values = append(values, v)
+		}
+	}
+
+	if len(values) == max-min+1 {
+		return "*"
+	}
+
+	if len(values) >= 3 {
+		step := values[1] - values[0]
+		progression := step > 1
+		for i := 2; i < len(values) && progression; i++ {
+			progression = values[i]-values[i-1] == step
+		}
+
+		if progression {
+			last := values[len(values)-1]
+			if values[0] == min && last+step > max {
+				return "*/" + strconv.Itoa(step)
+			}
+
+			return strconv.Itoa(values[0]) + "-" + strconv.Itoa(last) + "/" + strconv.Itoa(step)
+		}
+	}
+
+	var items []string
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+
+		if j-i >= 2 {
+			items = append(items, strconv.Itoa(values[i])+"-"+strconv.Itoa(values[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				items = append(items, strconv.Itoa(values[k]))
+			}
+		}
+
+		i = j + 1
+	}
+
+	return strings.Join(items, ",")
+}
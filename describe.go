@@ -0,0 +1,932 @@
+package schyntax
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// Describe returns an English description of a schedule. Each group is described in the order it appears in the text,
+// for example: "every 15 minutes from 09:00 to 16:59, Monday through Friday; and at midnight on December 24–26".
+func Describe(schedule string) (description string, err error) {
+	defer recoverError(schedule, &err)
+
+	ast := parse(schedule, nil)
+
+	type describedGroup struct {
+		index       int
+		expressions []*internals.ExpressionNode
+	}
+
+	var groups []describedGroup
+	if len(ast.Expressions) > 0 {
+		groups = append(groups, describedGroup{ast.Expressions[0].Index(), ast.Expressions})
+	}
+
+	for _, group := range ast.Groups {
+		if len(group.Expressions) > 0 {
+			groups = append(groups, describedGroup{group.Index(), group.Expressions})
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].index < groups[j].index
+	})
+
+	descriptions := make([]string, len(groups))
+	for i, group := range groups {
+		descriptions[i] = describeGroup(internals.CompileGroup(group.expressions))
+	}
+
+	if len(descriptions) < 2 {
+		return strings.Join(descriptions, ""), nil
+	}
+
+	last := len(descriptions) - 1
+	return strings.Join(descriptions[:last], "; ") + "; and " + descriptions[last], nil
+}
+
+func describeGroup(group *internals.IrGroup) string {
+	description := describeTimeOfDay(group)
+
+	if daysOfWeek := describeDaysOfWeek(group); daysOfWeek != "" {
+		description += ", " + daysOfWeek
+	}
+
+	for _, clause := range []string{
+		describeDays(group.DaysOfMonth, group.DaysOfMonthExcluded, 31, "of the month", false),
+		describeBusinessDays(group),
+		describeDays(group.DaysOfYear, group.DaysOfYearExcluded, 366, "of the year", true),
+		describeWeeks(group),
+		describeDates(group),
+		describeMonths(group),
+		describePeriods(group),
+		describeYears(group),
+		describeCalendars(group),
+	} {
+		if clause != "" {
+			description += " " + clause
+		}
+	}
+
+	if jitter := describeJitter(group); jitter != "" {
+		description += ", " + jitter
+	}
+
+	return description
+}
+
+/**********************************************************************************************
+ * Time of Day
+**********************************************************************************************/
+
+// the largest number of specific times which are listed rather than described unit by unit
+const maxDescribedTimes = 6
+
+func describeTimeOfDay(group *internals.IrGroup) string {
+	if times, ok := specificTimes(group); ok {
+		return "at " + joinList(times)
+	}
+
+	var milliseconds, seconds, minutes string
+	hasSeconds := len(group.Seconds) > 0 || len(group.SecondsExcluded) > 0
+	if !isOnlyZero(group.Milliseconds, group.MillisecondsExcluded) {
+		milliseconds = describeTimeUnit(group.Milliseconds, group.MillisecondsExcluded, "millisecond", 0, 999)
+		if !hasSeconds && strings.HasPrefix(milliseconds, "at ") {
+			milliseconds += " of every second"
+		}
+	}
+
+	// when milliseconds are described, seconds only need to be if they're restricted
+	if (milliseconds == "" && !isOnlyZero(group.Seconds, group.SecondsExcluded)) || (milliseconds != "" && hasSeconds) {
+		seconds = describeTimeUnit(group.Seconds, group.SecondsExcluded, "second", 0, 59)
+		if len(group.Minutes) == 0 && len(group.MinutesExcluded) == 0 && strings.HasPrefix(seconds, "at ") {
+			seconds += " of every minute"
+		}
+	}
+
+	if len(group.Minutes) > 0 || len(group.MinutesExcluded) > 0 || (seconds == "" && milliseconds == "") {
+		minutes = describeTimeUnit(group.Minutes, group.MinutesExcluded, "minute", 0, 59)
+	}
+
+	description := milliseconds
+	if seconds != "" {
+		if description != "" {
+			description += ", "
+		}
+
+		description += seconds
+	}
+
+	if minutes != "" {
+		if description != "" {
+			description += ", "
+		}
+
+		description += minutes
+	}
+
+	hours := describeHours(group.Hours, group.HoursExcluded)
+	if hours != "" {
+		return description + " " + hours
+	}
+
+	if minutes != "" && strings.HasPrefix(minutes, "at ") {
+		description += " of every hour"
+	}
+
+	return description
+}
+
+// specificTimes returns a list of times of day if the group only fires at a small number of them.
+func specificTimes(group *internals.IrGroup) ([]string, bool) {
+	if !isOnlyZero(group.Milliseconds, group.MillisecondsExcluded) {
+		return nil, false
+	}
+
+	seconds, ok1 := singleValues(group.Seconds, group.SecondsExcluded)
+	minutes, ok2 := singleValues(group.Minutes, group.MinutesExcluded)
+	hours, ok3 := singleValues(group.Hours, group.HoursExcluded)
+	if !ok1 || !ok2 || !ok3 || len(seconds)*len(minutes)*len(hours) > maxDescribedTimes {
+		return nil, false
+	}
+
+	var times []int
+	for _, h := range hours {
+		for _, m := range minutes {
+			for _, s := range seconds {
+				times = append(times, h*3600+m*60+s)
+			}
+		}
+	}
+
+	sort.Ints(times)
+
+	var descriptions []string
+	for i, t := range times {
+		if i > 0 && times[i-1] == t {
+			continue
+		}
+
+		switch {
+		case t == 0:
+			descriptions = append(descriptions, "midnight")
+		case t == 12*3600:
+			descriptions = append(descriptions, "noon")
+		case t%60 == 0:
+			descriptions = append(descriptions, fmt.Sprintf("%02d:%02d", t/3600, t/60%60))
+		default:
+			descriptions = append(descriptions, fmt.Sprintf("%02d:%02d:%02d", t/3600, t/60%60, t%60))
+		}
+	}
+
+	return descriptions, true
+}
+
+// singleValues returns the values of the ranges if every range is a single value and there are no exclusions.
+func singleValues(ranges, excluded []*internals.IrIntegerRange) ([]int, bool) {
+	if len(ranges) == 0 || len(excluded) > 0 {
+		return nil, false
+	}
+
+	values := make([]int, len(ranges))
+	for i, r := range ranges {
+		if r.IsRange {
+			return nil, false
+		}
+
+		values[i] = r.Start
+	}
+
+	return values, true
+}
+
+func isOnlyZero(ranges, excluded []*internals.IrIntegerRange) bool {
+	values, ok := singleValues(ranges, excluded)
+	if !ok {
+		return false
+	}
+
+	for _, v := range values {
+		if v != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describeTimeUnit describes the milliseconds, seconds or minutes of a group, such as "every 15 minutes" or "at minutes 0 and 30".
+func describeTimeUnit(ranges, excluded []*internals.IrIntegerRange, unit string, min, max int) string {
+	var description string
+	if len(ranges) == 0 {
+		description = "every " + unit
+	} else {
+		var singles []string
+		var phrases []string
+		for _, r := range ranges {
+			if !r.IsRange {
+				singles = append(singles, strconv.Itoa(r.Start))
+				continue
+			}
+
+			phrases = append(phrases, describeTimeUnitRange(r, unit, min, max))
+		}
+
+		if len(singles) > 0 {
+			phrases = append([]string{"at " + pluralize(unit, len(singles)) + " " + joinList(singles)}, phrases...)
+		}
+
+		description = strings.Join(phrases, " and ")
+	}
+
+	if len(excluded) > 0 {
+		var singles []string
+		var phrases []string
+		for _, r := range excluded {
+			if !r.IsRange {
+				singles = append(singles, strconv.Itoa(r.Start))
+				continue
+			}
+
+			phrases = append(phrases, describeTimeUnitRange(r, unit, min, max))
+		}
+
+		if len(singles) > 0 {
+			phrases = append([]string{pluralize(unit, len(singles)) + " " + joinList(singles)}, phrases...)
+		}
+
+		description += " except " + strings.Join(phrases, " and ")
+	}
+
+	return description
+}
+
+func describeTimeUnitRange(r *internals.IrIntegerRange, unit string, min, max int) string {
+	every := "every " + unit
+	if r.HasInterval {
+		every = "every " + strconv.Itoa(r.Interval) + " " + unit + "s"
+	}
+
+	if isFullRange(r, min, max) {
+		return every
+	}
+
+	return every + " from " + unit + " " + strconv.Itoa(r.Start) + " through " + strconv.Itoa(inclusiveEnd(r, min, max))
+}
+
+// describeHours describes the hours of a group, such as "from 09:00 to 16:59", or returns an empty string if every
+// hour is included.
+func describeHours(ranges, excluded []*internals.IrIntegerRange) string {
+	var phrases []string
+	for _, r := range ranges {
+		if phrase := describeHourRange(r); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+
+	description := strings.Join(phrases, " and ")
+
+	if len(excluded) > 0 {
+		phrases = nil
+		for _, r := range excluded {
+			phrase := describeHourRange(r)
+			if phrase == "" {
+				phrase = "every hour"
+			}
+
+			phrases = append(phrases, phrase)
+		}
+
+		if description != "" {
+			description += ","
+		}
+
+		description += " except " + strings.Join(phrases, " and ")
+	}
+
+	return strings.TrimSpace(description)
+}
+
+func describeHourRange(r *internals.IrIntegerRange) string {
+	var span string
+	if !isFullRange(r, 0, 23) {
+		end := r.Start
+		if r.IsRange {
+			end = inclusiveEnd(r, 0, 23)
+		}
+
+		span = fmt.Sprintf("from %02d:00 to %02d:59", r.Start, end)
+	}
+
+	if r.HasInterval {
+		if span == "" {
+			return "of every " + ordinal(r.Interval) + " hour"
+		}
+
+		return "of every " + ordinal(r.Interval) + " hour " + span
+	}
+
+	return span
+}
+
+/**********************************************************************************************
+ * Days
+**********************************************************************************************/
+
+func describeDaysOfWeek(group *internals.IrGroup) string {
+	var phrases []string
+	for _, r := range group.DaysOfWeek {
+		if isFullRange(r, 1, 7) && !r.HasInterval {
+			continue
+		}
+
+		phrases = append(phrases, describeDayOfWeekRange(r))
+	}
+
+	description := joinList(phrases)
+
+	if len(group.DaysOfWeekExcluded) > 0 {
+		phrases = nil
+		for _, r := range group.DaysOfWeekExcluded {
+			phrases = append(phrases, describeDayOfWeekRange(r))
+		}
+
+		if description == "" {
+			description = "every day"
+		}
+
+		description += " except " + joinList(phrases)
+	}
+
+	return description
+}
+
+func describeDayOfWeekRange(r *internals.IrIntegerRange) string {
+	if r.HasNth {
+		if r.Nth == -1 {
+			return "the last " + dayOfWeekName(r.Start) + " of the month"
+		}
+
+		if r.Nth < 0 {
+			return "the " + ordinal(-r.Nth) + " to last " + dayOfWeekName(r.Start) + " of the month"
+		}
+
+		return "the " + ordinal(r.Nth) + " " + dayOfWeekName(r.Start) + " of the month"
+	}
+
+	if !r.IsRange {
+		return dayOfWeekName(r.Start)
+	}
+
+	var span string
+	if !isFullRange(r, 1, 7) {
+		span = dayOfWeekName(r.Start) + " through " + dayOfWeekName(inclusiveEnd(r, 1, 7))
+	}
+
+	if r.HasInterval {
+		if span == "" {
+			return "every " + ordinal(r.Interval) + " day of the week"
+		}
+
+		return "every " + ordinal(r.Interval) + " day from " + span
+	}
+
+	return span
+}
+
+func dayOfWeekName(day int) string {
+	return time.Weekday(day - 1).String()
+}
+
+// describeDays describes the days of the month or year, such as "on the 1st and 15th of the month".
+func describeDays(ranges, excluded []*internals.IrIntegerRange, max int, suffix string, dayAfterOrdinal bool) string {
+	var description string
+
+	var phrases []string
+	for _, r := range ranges {
+		if isFullRange(r, 1, max) && !r.HasInterval {
+			continue
+		}
+
+		phrases = append(phrases, describeDayRange(r, max, dayAfterOrdinal))
+	}
+
+	if len(phrases) > 0 {
+		description = "on " + joinList(phrases) + " " + suffix
+	}
+
+	if len(excluded) > 0 {
+		phrases = nil
+		for _, r := range excluded {
+			phrases = append(phrases, describeDayRange(r, max, dayAfterOrdinal))
+		}
+
+		if description != "" {
+			description += ", "
+		}
+
+		description += "except on " + joinList(phrases) + " " + suffix
+	}
+
+	return description
+}
+
+func describeDayRange(r *internals.IrIntegerRange, max int, dayAfterOrdinal bool) string {
+	if !r.IsRange {
+		return "the " + dayOrdinal(r.Start, dayAfterOrdinal)
+	}
+
+	var span string
+	if !isFullRange(r, 1, max) {
+		span = "the " + dayOrdinal(r.Start, dayAfterOrdinal) + " through the " + dayOrdinal(inclusiveEnd(r, 1, max), dayAfterOrdinal)
+	}
+
+	if r.HasInterval {
+		if span == "" {
+			return "every " + ordinal(r.Interval) + " day"
+		}
+
+		return "every " + ordinal(r.Interval) + " day from " + span
+	}
+
+	return span
+}
+
+// dayOrdinal returns "1st" for 1, "last day" for -1 and "2nd to last day" for -2. If dayAfterOrdinal is true, positive
+// days are also followed by " day".
+func dayOrdinal(day int, dayAfterOrdinal bool) string {
+	if day == -1 {
+		return "last day"
+	}
+
+	if day < 0 {
+		return ordinal(-day) + " to last day"
+	}
+
+	if dayAfterOrdinal {
+		return ordinal(day) + " day"
+	}
+
+	return ordinal(day)
+}
+
+// describeBusinessDays describes business days, such as "on the 3rd business day of the month" or "on the last
+// business day before the 15th". A wildcard only restricts the group to business days.
+func describeBusinessDays(group *internals.IrGroup) string {
+	if !group.HasBusinessDays() && !group.HasBusinessDaysExcluded() {
+		return ""
+	}
+
+	description := "on business days"
+	if phrases := businessDayPhrases(group.BusinessDays); len(phrases) > 0 {
+		description = "on " + joinList(phrases)
+	}
+
+	if len(group.BusinessDaysExcluded) > 0 {
+		description += ", except on " + joinList(businessDayPhrases(group.BusinessDaysExcluded))
+	}
+
+	return description
+}
+
+// businessDayPhrases returns a phrase for each run of ranges which count from the same place, such as "the 1st and the
+// 2nd business day of the month".
+func businessDayPhrases(ranges []*internals.IrIntegerRange) []string {
+	var phrases []string
+	var spans []string
+	var suffix string
+	for _, r := range ranges {
+		if r.From == 0 && isFullRange(r, 1, 31) && !r.HasInterval {
+			continue
+		}
+
+		s := " of the month"
+		if r.From != 0 && r.Start < 0 {
+			s = " before the " + ordinal(r.From)
+		} else if r.From != 0 {
+			s = " on or after the " + ordinal(r.From)
+		}
+
+		if len(spans) > 0 && s != suffix {
+			phrases = append(phrases, joinList(spans)+suffix)
+			spans = nil
+		}
+
+		spans = append(spans, describeBusinessDayRange(r))
+		suffix = s
+	}
+
+	if len(spans) > 0 {
+		phrases = append(phrases, joinList(spans)+suffix)
+	}
+
+	return phrases
+}
+
+func describeBusinessDayRange(r *internals.IrIntegerRange) string {
+	if !r.IsRange {
+		return "the " + businessDayOrdinal(r.Start)
+	}
+
+	var span string
+	if !isFullRange(r, 1, 31) {
+		span = "the " + businessDayOrdinal(r.Start) + " through the " + businessDayOrdinal(inclusiveEnd(r, 1, 31))
+	}
+
+	if r.HasInterval {
+		if span == "" {
+			return "every " + ordinal(r.Interval) + " business day"
+		}
+
+		return "every " + ordinal(r.Interval) + " business day from " + span
+	}
+
+	if span == "" {
+		return "every business day"
+	}
+
+	return span
+}
+
+// businessDayOrdinal returns "1st business day" for 1, "last business day" for -1 and "2nd to last business day" for -2.
+func businessDayOrdinal(day int) string {
+	if day == -1 {
+		return "last business day"
+	}
+
+	if day < 0 {
+		return ordinal(-day) + " to last business day"
+	}
+
+	return ordinal(day) + " business day"
+}
+
+func describeWeeks(group *internals.IrGroup) string {
+	var description string
+
+	var phrases []string
+	for _, r := range group.Weeks {
+		if isFullRange(r, 1, 53) && !r.HasInterval {
+			continue
+		}
+
+		phrases = append(phrases, describeWeekRange(r))
+	}
+
+	if len(phrases) > 0 {
+		description = "in " + joinList(phrases)
+	}
+
+	if len(group.WeeksExcluded) > 0 {
+		phrases = nil
+		for _, r := range group.WeeksExcluded {
+			phrases = append(phrases, describeWeekRange(r))
+		}
+
+		if description != "" {
+			description += ", "
+		}
+
+		description += "except in " + joinList(phrases)
+	}
+
+	if description == "" {
+		return ""
+	}
+
+	return description + " of the ISO year"
+}
+
+func describeWeekRange(r *internals.IrIntegerRange) string {
+	if !r.IsRange {
+		return weekName(r.Start)
+	}
+
+	var span string
+	if !isFullRange(r, 1, 53) {
+		span = weekName(r.Start) + " through " + weekName(inclusiveEnd(r, 1, 53))
+	}
+
+	if r.HasInterval {
+		if span == "" {
+			return "every " + ordinal(r.Interval) + " week"
+		}
+
+		return "every " + ordinal(r.Interval) + " week from " + span
+	}
+
+	return span
+}
+
+// weekName returns "week 1" for 1, "the last week" for -1 and "the 2nd to last week" for -2.
+func weekName(week int) string {
+	if week == -1 {
+		return "the last week"
+	}
+
+	if week < 0 {
+		return "the " + ordinal(-week) + " to last week"
+	}
+
+	return "week " + strconv.Itoa(week)
+}
+
+func describeMonths(group *internals.IrGroup) string {
+	var description string
+
+	var phrases []string
+	for _, r := range group.Months {
+		if isFullRange(r, 1, 12) && !r.HasInterval {
+			continue
+		}
+
+		phrases = append(phrases, describeMonthRange(r))
+	}
+
+	if len(phrases) > 0 {
+		description = "in " + joinList(phrases)
+	}
+
+	if len(group.MonthsExcluded) > 0 {
+		phrases = nil
+		for _, r := range group.MonthsExcluded {
+			phrases = append(phrases, describeMonthRange(r))
+		}
+
+		if description != "" {
+			description += ", "
+		}
+
+		description += "except in " + joinList(phrases)
+	}
+
+	return description
+}
+
+func describeMonthRange(r *internals.IrIntegerRange) string {
+	if !r.IsRange {
+		return time.Month(r.Start).String()
+	}
+
+	var span string
+	if !isFullRange(r, 1, 12) {
+		span = time.Month(r.Start).String() + " through " + time.Month(inclusiveEnd(r, 1, 12)).String()
+	}
+
+	if r.HasInterval {
+		if span == "" {
+			return "every " + ordinal(r.Interval) + " month"
+		}
+
+		return "every " + ordinal(r.Interval) + " month from " + span
+	}
+
+	return span
+}
+
+func describePeriods(group *internals.IrGroup) string {
+	var phrases []string
+	for _, p := range group.Periods {
+		amount, unit := p.Days, "day"
+		if p.Days%7 == 0 {
+			amount, unit = p.Days/7, "week"
+		}
+
+		phrase := "every " + unit
+		if amount > 1 {
+			phrase = "every " + strconv.Itoa(amount) + " " + pluralize(unit, amount)
+		}
+
+		phrases = append(phrases, phrase+" from "+describeDate(p.From))
+	}
+
+	return joinList(phrases)
+}
+
+func describeYears(group *internals.IrGroup) string {
+	var description string
+
+	var phrases []string
+	for _, r := range group.Years {
+		phrases = append(phrases, describeYearRange(r))
+	}
+
+	if len(phrases) > 0 {
+		description = "in " + joinList(phrases)
+	}
+
+	if len(group.YearsExcluded) > 0 {
+		phrases = nil
+		for _, r := range group.YearsExcluded {
+			phrases = append(phrases, describeYearRange(r))
+		}
+
+		if description != "" {
+			description += ", "
+		}
+
+		description += "except in " + joinList(phrases)
+	}
+
+	return description
+}
+
+func describeYearRange(r *internals.IrIntegerRange) string {
+	if !r.IsRange {
+		return strconv.Itoa(r.Start)
+	}
+
+	// ranges with intervals run to the last valid year when they don't have an end
+	end := inclusiveEnd(r, 1900, 2200)
+	if r.HasInterval {
+		if end == 2200 {
+			return "every " + ordinal(r.Interval) + " year from " + strconv.Itoa(r.Start)
+		}
+
+		return "every " + ordinal(r.Interval) + " year from " + strconv.Itoa(r.Start) + " through " + strconv.Itoa(end)
+	}
+
+	return strconv.Itoa(r.Start) + " through " + strconv.Itoa(end)
+}
+
+func describeDates(group *internals.IrGroup) string {
+	var description string
+
+	var phrases []string
+	for _, r := range group.Dates {
+		phrases = append(phrases, describeDateRange(r))
+	}
+
+	if len(phrases) > 0 {
+		description = "on " + joinList(phrases)
+	}
+
+	if len(group.DatesExcluded) > 0 {
+		phrases = nil
+		for _, r := range group.DatesExcluded {
+			phrases = append(phrases, describeDateRange(r))
+		}
+
+		if description != "" {
+			description += ", "
+		}
+
+		description += "except on " + joinList(phrases)
+	}
+
+	return description
+}
+
+func describeDateRange(r *internals.IrDateRange) string {
+	if !r.IsRange {
+		return describeDate(r.Start)
+	}
+
+	var span string
+	switch {
+	case r.IsHalfOpen:
+		span = describeDate(r.Start) + " up to but not including " + describeDate(r.End)
+	case r.Start.Month == r.End.Month && r.Start.Year == r.End.Year && r.Start.Day < r.End.Day:
+		span = time.Month(r.Start.Month).String() + " " + strconv.Itoa(r.Start.Day) + "–" + strconv.Itoa(r.End.Day)
+		if r.DatesHaveYear {
+			span += ", " + strconv.Itoa(r.Start.Year)
+		}
+	default:
+		span = describeDate(r.Start) + " through " + describeDate(r.End)
+	}
+
+	if r.HasInterval {
+		return "every " + ordinal(r.Interval) + " day from " + span
+	}
+
+	return span
+}
+
+func describeDate(date *internals.IrDate) string {
+	description := time.Month(date.Month).String() + " " + strconv.Itoa(date.Day)
+	if date.Year != 0 {
+		description += ", " + strconv.Itoa(date.Year)
+	}
+
+	return description
+}
+
+/**********************************************************************************************
+ * Calendars
+**********************************************************************************************/
+
+func describeCalendars(group *internals.IrGroup) string {
+	var description string
+	if len(group.Calendars) > 0 {
+		description = "in " + calendarList(group.Calendars)
+	}
+
+	if len(group.CalendarsExcluded) > 0 {
+		if description != "" {
+			description += ", "
+		}
+
+		description += "except in " + calendarList(group.CalendarsExcluded)
+	}
+
+	return description
+}
+
+// calendarList returns a phrase such as `calendar "a"` or `calendars "a" or "b"`.
+func calendarList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+
+	if len(quoted) == 1 {
+		return "calendar " + quoted[0]
+	}
+
+	return "calendars " + strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+/**********************************************************************************************
+ * Jitter
+**********************************************************************************************/
+
+func describeJitter(group *internals.IrGroup) string {
+	if group.Jitter == 0 {
+		return ""
+	}
+
+	amount, unit := group.Jitter, "millisecond"
+	for _, u := range []struct {
+		ms   int
+		name string
+	}{{60 * 60 * 1000, "hour"}, {60 * 1000, "minute"}, {1000, "second"}} {
+		if group.Jitter%u.ms == 0 {
+			amount, unit = group.Jitter/u.ms, u.name
+			break
+		}
+	}
+
+	return "delayed by less than " + strconv.Itoa(amount) + " " + pluralize(unit, amount)
+}
+
+/**********************************************************************************************
+ * Helpers
+**********************************************************************************************/
+
+// isFullRange returns true if the range covers every value from min to max, such as a wildcard.
+func isFullRange(r *internals.IrIntegerRange, min, max int) bool {
+	return r.IsRange && !r.IsSplit && !r.IsHalfOpen && r.Start == min && r.End == max
+}
+
+// inclusiveEnd returns the last value of a range, accounting for half-open ranges.
+func inclusiveEnd(r *internals.IrIntegerRange, min, max int) int {
+	if !r.IsHalfOpen {
+		return r.End
+	}
+
+	if r.End == min {
+		return max // the range loops back around, so the last value is the max
+	}
+
+	return r.End - 1
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return strconv.Itoa(n) + suffix
+}
+
+func pluralize(unit string, count int) string {
+	if count == 1 {
+		return unit
+	}
+
+	return unit + "s"
+}
+
+// joinList joins items as an English list, such as "a, b and c".
+func joinList(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
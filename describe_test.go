@@ -0,0 +1,63 @@
+package schyntax
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		schedule    string
+		description string
+	}{
+		{"{dow(mon..fri) h(9..<17) min(*%15)}, dates(12/24..12/26) h(0)", "every 15 minutes from 09:00 to 16:59, Monday through Friday; and at midnight on December 24–26"},
+		{"{h(9)}, {h(12)}, {h(18) min(30)}", "at 09:00; at noon; and at 18:30"},
+		{"min(*%5)", "every 5 minutes"},
+		{"s(30)", "at second 30 of every minute"},
+		{"ms(0, 500)", "at milliseconds 0 and 500 of every second"},
+		{"ms(*%250) s(0)", "every 250 milliseconds, at second 0 of every minute"},
+		{"min(0, 30)", "at minutes 0 and 30 of every hour"},
+		{"h(9, 17)", "at 09:00 and 17:00"},
+		{"h(*%2) min(0)", "at minute 0 of every 2nd hour"},
+		{"h(!12) min(0, 15, 30, 45)", "at minutes 0, 15, 30 and 45 except from 12:00 to 12:59"},
+		{"h(22..<2) min(*)", "every minute from 22:00 to 01:59"},
+		{"h(22..<0)", "at minute 0 from 22:00 to 23:59"},
+		{"min(50..<0)", "every minute from minute 50 through 59"},
+		{"s(30..<0)", "every second from second 30 through 59"},
+		{"min(!*%5)", "every minute except every 5 minutes"},
+		{"doy(-1) h(23) min(59) s(59)", "at 23:59:59 on the last day of the year"},
+		{"dom(-7..-1) h(12)", "at noon on the 7th to last day through the last day of the month"},
+		{"dom(1, 15, !10)", "at midnight on the 1st and the 15th of the month, except on the 10th of the month"},
+		{"dow(fri#-1, mon#2)", "at midnight, the last Friday of the month and the 2nd Monday of the month"},
+		{"dow(!sat, !sun) h(6)", "at 06:00, every day except Saturday and Sunday"},
+		{"dow(sat..mon)", "at midnight, Saturday through Monday"},
+		{"months(jan..mar, !feb)", "at midnight in January through March, except in February"},
+		{"months(*%3) dom(1)", "at midnight on the 1st of the month in every 3rd month"},
+		{"dates(12/24..1/2)", "at midnight on December 24 through January 2"},
+		{"dates(3/1..<3/10)", "at midnight on March 1 up to but not including March 10"},
+		{"dates(1/1) years(2024%4, !2032)", "at midnight on January 1 in every 4th year from 2024, except in 2032"},
+		{"dow(mon) weeks(*%2)", "at midnight, Monday in every 2nd week of the ISO year"},
+		{"h(9) weeks(1..26, !-1)", "at 09:00 in week 1 through week 26, except in the last week of the ISO year"},
+		{"every(2w, from 2025/1/6) h(9)", "at 09:00 every 2 weeks from January 6, 2025"},
+		{"every(1d, from 2025/1/6)", "at midnight every day from January 6, 2025"},
+		{"bday(3) h(9)", "at 09:00 on the 3rd business day of the month"},
+		{"bday(1, -1) bday(-1, from 15)", "at midnight on the 1st business day and the last business day of the month and the last business day before the 15th"},
+		{"bday(1, !-1, from 16)", "at midnight on the 1st business day on or after the 16th, except on the last business day before the 16th"},
+		{"bday(*) h(9)", "at 09:00 on business days"},
+		{"h(9) years(2025..<2028)", "at 09:00 in 2025 through 2027"},
+		{"h(9) cal(business-days, easter, !us-federal)", `at 09:00 in calendars "business-days" or "easter", except in calendar "us-federal"`},
+	}
+
+	for _, test := range tests {
+		description, err := Describe(test.schedule)
+		if err != nil {
+			t.Errorf("%q: %s", test.schedule, err)
+			continue
+		}
+
+		if description != test.description {
+			t.Errorf("%q.\nExpected: %q\nActual:   %q", test.schedule, test.description, description)
+		}
+	}
+
+	if _, err := Describe("min(60)"); err == nil {
+		t.Error("Expected an error")
+	}
+}
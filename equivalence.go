@@ -0,0 +1,251 @@
+package schyntax
+
+import (
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// Difference is an instant at which one schedule fires and another doesn't.
+type Difference struct {
+	Time time.Time
+	// Fired is the schedule which fires at Time.
+	Fired Schedule
+	// Missed is the schedule which doesn't fire at Time.
+	Missed Schedule
+}
+
+// Every pattern of dates repeats after the Gregorian calendar's 400 year cycle, except for dates with explicit years,
+// which can't be later than 2200. Comparing one full cycle after 2200 therefore covers every pattern of days which can
+// happen later.
+var equivalenceCycleStart = time.Date(2201, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// the number of days in the Gregorian calendar's 400 year cycle
+const gregorianCycleDays = 146097
+
+// Equivalent returns nil if a and b fire at exactly the same instants at or after from, at any point in the future.
+// Otherwise, it returns the first instant at or after from at which only one of them fires.
+//
+// When both schedules are evaluated in the same location, the comparison is symbolic: each day is reduced to the set of
+// wall clock times which its applicable groups match, and days are compared through the end of the first 400 year
+// calendar cycle after 2200, after which every pattern repeats. Otherwise, the schedules are compared exhaustively for
+// the larger of their search horizons, in days, after from. Calendars, every expressions and holidays don't have to
+// follow that cycle, so schedules which use them are also only compared for their search horizons, although still
+// symbolically when possible.
+func Equivalent(a, b Schedule, from time.Time) *Difference {
+	left, right, ok := symbolicPair(a, b)
+	if !ok || !left.repeatsEachCycle() || !right.repeatsEachCycle() {
+		horizon := 0
+		for _, s := range []Schedule{a, b} {
+			if impl, ok := s.(*scheduleImpl); ok {
+				horizon = max(horizon, impl.horizon)
+			}
+		}
+
+		if horizon == 0 {
+			horizon = DefaultSearchHorizon
+		}
+
+		return Diff(a, b, from, from.AddDate(0, 0, horizon))
+	}
+
+	cycleStart := wallClock(from.In(left.loc))
+	if cycleStart.Before(equivalenceCycleStart) {
+		cycleStart = equivalenceCycleStart
+	}
+
+	end := cycleStart.AddDate(0, 0, gregorianCycleDays+1)
+	return diffDays(left, right, from, localTime(end.Year(), int(end.Month()), end.Day(), 0, 0, 0, left.loc))
+}
+
+// Diff returns the first instant at or after from, and before to, at which only one of a and b fires, or nil if they
+// fire at exactly the same instants during that window. When both schedules are evaluated in the same location, days on
+// which they match the same wall clock times are skipped without searching them, so large windows are cheap.
+func Diff(a, b Schedule, from, to time.Time) *Difference {
+	if left, right, ok := symbolicPair(a, b); ok {
+		return diffDays(left, right, from, to)
+	}
+
+	return diffOccurrences(a, b, from, to)
+}
+
+// symbolicPair returns the implementations of a and b if they can be compared symbolically, which isn't possible when
+// either has jitter.
+func symbolicPair(a, b Schedule) (left, right *scheduleImpl, ok bool) {
+	left, ok = a.(*scheduleImpl)
+	if !ok || left.hasJitter() {
+		return nil, nil, false
+	}
+
+	right, ok = b.(*scheduleImpl)
+	if !ok || right.hasJitter() || left.loc.String() != right.loc.String() {
+		return nil, nil, false
+	}
+
+	return left, right, true
+}
+
+// repeatsEachCycle returns false if the schedule uses calendars, every expressions or business days with holidays, whose
+// days don't necessarily repeat every 400 years.
+func (s *scheduleImpl) repeatsEachCycle() bool {
+	if s.calendars != nil || (s.holidays != nil && s.usesBusinessDays()) {
+		return false
+	}
+
+	for _, group := range s.ir.Groups {
+		if group.HasPeriods() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// diffOccurrences compares the events of a and b one at a time.
+func diffOccurrences(a, b Schedule, from, to time.Time) *Difference {
+	nextA, stopA := iter.Pull(a.Occurrences(from, to))
+	defer stopA()
+
+	nextB, stopB := iter.Pull(b.Occurrences(from, to))
+	defer stopB()
+
+	eventA, okA := nextA()
+	eventB, okB := nextB()
+	for okA || okB {
+		if !okB || (okA && eventA.Before(eventB)) {
+			return &Difference{eventA, a, b}
+		}
+
+		if !okA || eventB.Before(eventA) {
+			return &Difference{eventB, b, a}
+		}
+
+		eventA, okA = nextA()
+		eventB, okB = nextB()
+	}
+
+	return nil
+}
+
+// diffDays compares two schedules in the same location one day at a time, and only searches the days on which they
+// match different wall clock times.
+func diffDays(a, b *scheduleImpl, from, to time.Time) *Difference {
+	setsA := newDayTimeSets(a)
+	setsB := newDayTimeSets(b)
+	equal := make(map[string]bool)
+
+	last := wallClock(to.In(a.loc))
+	for day := wallClock(from.In(a.loc)); !day.After(last); day = day.AddDate(0, 0, 1) {
+		keyA, setA := setsA.at(day)
+		keyB, setB := setsB.at(day)
+
+		key := keyA + "|" + keyB
+		same, ok := equal[key]
+		if !ok {
+			same = setA.equal(setB)
+			equal[key] = same
+		}
+
+		if same {
+			continue
+		}
+
+		start := localTime(day.Year(), int(day.Month()), day.Day(), 0, 0, 0, a.loc)
+		next := day.AddDate(0, 0, 1)
+		end := localTime(next.Year(), int(next.Month()), next.Day(), 0, 0, 0, a.loc)
+		if start.Before(from) {
+			start = from
+		}
+
+		if end.After(to) {
+			end = to
+		}
+
+		// a daylight saving transition can make different wall clock times fire at the same instants
+		if d := diffOccurrences(a, b, start, end); d != nil {
+			return d
+		}
+	}
+
+	return nil
+}
+
+// timeOfDaySet holds, for each set of milliseconds which a schedule fires at, a bitmask of the seconds of each hour and
+// minute during which it fires at exactly those milliseconds.
+type timeOfDaySet map[internals.MillisecondsMask]*[24][60]uint64
+
+func (t timeOfDaySet) equal(other timeOfDaySet) bool {
+	if len(t) != len(other) {
+		return false
+	}
+
+	for milliseconds, seconds := range t {
+		if o, ok := other[milliseconds]; !ok || *o != *seconds {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dayTimeSets calculates the wall clock times at which a schedule fires on each day. Days on which the same groups are
+// applicable share the same set.
+type dayTimeSets struct {
+	schedule *scheduleImpl
+	sets     map[string]timeOfDaySet
+}
+
+func newDayTimeSets(s *scheduleImpl) *dayTimeSets {
+	return &dayTimeSets{s, make(map[string]timeOfDaySet)}
+}
+
+// at returns a key identifying the groups which are applicable on date, and the times at which they fire.
+func (d *dayTimeSets) at(date time.Time) (string, timeOfDaySet) {
+	var sb strings.Builder
+	var groups []*internals.IrGroup
+	for _, group := range d.schedule.ir.Groups {
+		if d.schedule.isApplicableDate(group, date) {
+			sb.WriteByte('1')
+			groups = append(groups, group)
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+
+	key := sb.String()
+	if set, ok := d.sets[key]; ok {
+		return key, set
+	}
+
+	set := make(timeOfDaySet)
+	for h := 0; h < 24; h++ {
+		for m := 0; m < 60; m++ {
+			for sec := 0; sec < 60; sec++ {
+				// the milliseconds of every group which fires during this second
+				var milliseconds internals.MillisecondsMask
+				for _, group := range groups {
+					if group.HoursMask&(1<<uint(h)) != 0 && group.MinutesMask&(1<<uint(m)) != 0 && group.SecondsMask&(1<<uint(sec)) != 0 {
+						milliseconds = milliseconds.Or(group.MillisecondsMask)
+					}
+				}
+
+				if milliseconds.IsEmpty() {
+					continue
+				}
+
+				seconds, ok := set[milliseconds]
+				if !ok {
+					seconds = &[24][60]uint64{}
+					set[milliseconds] = seconds
+				}
+
+				seconds[h][m] |= 1 << uint(sec)
+			}
+		}
+	}
+
+	d.sets[key] = set
+	return key, set
+}
@@ -0,0 +1,88 @@
+package schyntax
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEquivalent(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	equivalent := [][2]string{
+		{"hours(9..17) days(MONDAY..friday)", "{dow(mon..fri) h(9..17) min(0) s(0)}"},
+		{"min(*%15)", "min(0, 15, 30, 45)"},
+		{"dow(mon..fri)", "dow(!sat, !sun)"},
+		{"{h(9)}, {h(10)}", "h(9..10)"},
+		{"dom(-1) months(feb)", "dates(2/28..2/29) dom(!1..27, !-2)"},
+		{"dates(12/30..1/2)", "{dates(12/30..12/31)}, {dom(1..2) months(jan)}"},
+		{"dates(2020/1/1) h(1)", "dates(2020/1/1) h(2)"}, // they only differ before from
+		{"ms(0, 500) s(0)", "{ms(0) s(0)}, {ms(500) s(0)}"},
+		{"h(*) min(*) s(*)", "ms(0)"},
+	}
+
+	for _, pair := range equivalent {
+		a, _ := New(pair[0])
+		b, _ := New(pair[1])
+		if d := Equivalent(a, b, from); d != nil {
+			t.Errorf("%q and %q: unexpected difference at %s", pair[0], pair[1], d.Time)
+		}
+	}
+
+	different := []struct {
+		a, b   string
+		time   time.Time
+		firedA bool
+	}{
+		{"h(9..17)", "h(9..16)", time.Date(2025, 1, 1, 17, 0, 0, 0, time.UTC), true},
+		{"dow(mon..fri)", "dow(mon..thu)", time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), true},
+		{"dom(29) months(feb)", "dom(-1) months(feb)", time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), false},
+		{"doy(366)", "dates(12/31) dom(31)", time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"dates(12/31) h(*)", "dates(12/31) h(*) dow(!sun)", time.Date(2028, 12, 31, 0, 0, 0, 0, time.UTC), true},
+		{"ms(0) s(0)", "ms(0, 500) s(0)", time.Date(2025, 1, 1, 0, 0, 0, int(500*time.Millisecond), time.UTC), false},
+	}
+
+	for _, test := range different {
+		a, _ := New(test.a)
+		b, _ := New(test.b)
+		d := Equivalent(a, b, from)
+		if d == nil {
+			t.Errorf("%q and %q: expected a difference", test.a, test.b)
+			continue
+		}
+
+		if !d.Time.Equal(test.time) || (d.Fired == a) != test.firedA || (d.Missed == a) == test.firedA {
+			t.Errorf("%q and %q: unexpected difference at %s (fired: %q)", test.a, test.b, d.Time, d.Fired.OriginalText())
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, loc)
+	to := time.Date(2026, 1, 1, 0, 0, 0, 0, loc)
+
+	a, _ := NewInLocation("h(2) min(30)", loc)
+	b, _ := NewInLocation("h(2) min(30) dates(!3/9)", loc)
+	d := Diff(a, b, from, to)
+	if d == nil || d.Fired != a || !d.Time.Equal(time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a to fire at the daylight saving transition, got %+v", d)
+	}
+
+	if d := Diff(a, b, d.Time.Add(time.Second), to); d != nil {
+		t.Errorf("Expected no more differences, got %s", d.Time)
+	}
+
+	// schedules in different locations are compared one event at a time
+	utc, _ := New("h(7) min(30)")
+	d = Diff(a, utc, from, to)
+	if d == nil || d.Fired != a || !d.Time.Equal(time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the New York schedule to fire first at the transition, got %+v", d)
+	}
+
+	if d := Diff(a, utc, from, time.Date(2025, 3, 1, 0, 0, 0, 0, loc)); d != nil {
+		t.Errorf("Expected no differences before the transition, got %s", d.Time)
+	}
+}
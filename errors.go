@@ -1,6 +1,9 @@
 package schyntax
 
-import "github.com/schyntax/go-schyntax/internals"
+import (
+	"github.com/schyntax/go-schyntax/internals"
+	"strconv"
+)
 
 type SchyntaxError interface {
 	Error() string
@@ -9,15 +12,50 @@ type SchyntaxError interface {
 }
 
 var _ SchyntaxError = &internals.ParseError{}
+var _ SchyntaxError = &internals.ParseErrorList{}
 var _ SchyntaxError = &ValidTimeNotFoundError{}
 var _ SchyntaxError = &InternalError{}
+var _ SchyntaxError = &UnsatisfiableError{}
+
+// SearchDirection is the direction in which a schedule is searched for an event.
+type SearchDirection int8
+
+const (
+	SearchForward SearchDirection = iota
+	SearchBackward
+)
+
+func (d SearchDirection) String() string {
+	if d == SearchForward {
+		return "forward"
+	}
+
+	return "backward"
+}
 
 type ValidTimeNotFoundError struct {
-	input string
+	input     string
+	horizon   int
+	direction SearchDirection
 }
 
 func (e *ValidTimeNotFoundError) Error() string {
-	return "A valid time was not found for the schedule."
+	relation := "after"
+	if e.direction == SearchBackward {
+		relation = "at or before"
+	}
+
+	return "A valid time was not found for the schedule within " + strconv.Itoa(e.horizon) + " days " + relation + " the start time."
+}
+
+// Horizon returns the number of days which were searched.
+func (e *ValidTimeNotFoundError) Horizon() int {
+	return e.horizon
+}
+
+// Direction returns the direction in which the schedule was searched.
+func (e *ValidTimeNotFoundError) Direction() SearchDirection {
+	return e.direction
 }
 
 func (e *ValidTimeNotFoundError) Input() string {
@@ -0,0 +1,186 @@
+package schyntax
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// the canonical spelling of each expression name, day literal and month literal
+var canonicalExpressionNames = map[internals.ExpressionType]string{
+	internals.ExpressionTypeMilliseconds: "ms",
+	internals.ExpressionTypeSeconds:      "s",
+	internals.ExpressionTypeMinutes:      "min",
+	internals.ExpressionTypeHours:        "h",
+	internals.ExpressionTypeDaysOfWeek:   "dow",
+	internals.ExpressionTypeDaysOfMonth:  "dom",
+	internals.ExpressionTypeDaysOfYear:   "doy",
+	internals.ExpressionTypeBusinessDays: "bday",
+	internals.ExpressionTypeWeeks:        "weeks",
+	internals.ExpressionTypeMonths:       "months",
+	internals.ExpressionTypeDates:        "dates",
+	internals.ExpressionTypeYears:        "years",
+	internals.ExpressionTypeEvery:        "every",
+	internals.ExpressionTypeJitter:       "jitter",
+	internals.ExpressionTypeCalendar:     "cal",
+}
+
+// the canonical spelling of each duration unit, by the value of its token
+var durationUnits = map[string]string{"MS": "ms", "S": "s", "MIN": "min", "H": "h", "D": "d", "W": "w"}
+
+var dayLiterals = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+var monthLiterals = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+// the order of expressions when the SortExpressions option is used
+var expressionSortOrder = map[internals.ExpressionType]int{
+	internals.ExpressionTypeMilliseconds: 0,
+	internals.ExpressionTypeSeconds:      1,
+	internals.ExpressionTypeMinutes:      2,
+	internals.ExpressionTypeHours:        3,
+	internals.ExpressionTypeDaysOfWeek:   4,
+	internals.ExpressionTypeDaysOfMonth:  5,
+	internals.ExpressionTypeBusinessDays: 6,
+	internals.ExpressionTypeDaysOfYear:   7,
+	internals.ExpressionTypeWeeks:        8,
+	internals.ExpressionTypeMonths:       9,
+	internals.ExpressionTypeDates:        10,
+	internals.ExpressionTypeEvery:        11,
+	internals.ExpressionTypeYears:        12,
+	internals.ExpressionTypeCalendar:     13,
+	internals.ExpressionTypeJitter:       14,
+}
+
+// FormatOption configures Format.
+type FormatOption func(*formatOptions)
+
+type formatOptions struct {
+	sortExpressions bool
+}
+
+// SortExpressions causes Format to order the expressions within each group from the smallest unit (milliseconds) to the
+// largest (years). Expressions of the same type keep their original order.
+func SortExpressions() FormatOption {
+	return func(o *formatOptions) {
+		o.sortExpressions = true
+	}
+}
+
+// Format returns the canonical text of a schedule. Expression names, day literals and month literals use a single
+// spelling (for example, "min" rather than "minutesOfHour", and "mon" rather than "Monday"), arguments are separated by
+// ", ", expressions are separated by a space, and groups are separated by ", ", with free-floating expressions first.
+// Empty groups are removed. The formatted schedule always compiles to the same result as the original.
+func Format(schedule string, options ...FormatOption) (formatted string, err error) {
+	defer recoverError(schedule, &err)
+
+	var opts formatOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	ast := parse(schedule, nil)
+
+	var parts []string
+	if len(ast.Expressions) > 0 {
+		parts = append(parts, formatExpressions(ast.Expressions, &opts))
+	}
+
+	for _, group := range ast.Groups {
+		if len(group.Expressions) > 0 {
+			parts = append(parts, "{"+formatExpressions(group.Expressions, &opts)+"}")
+		}
+	}
+
+	return strings.Join(parts, ", "), nil
+}
+
+func formatExpressions(expressions []*internals.ExpressionNode, opts *formatOptions) string {
+	if opts.sortExpressions {
+		expressions = append([]*internals.ExpressionNode(nil), expressions...)
+		sort.SliceStable(expressions, func(i, j int) bool {
+			return expressionSortOrder[expressions[i].ExpressionType] < expressionSortOrder[expressions[j].ExpressionType]
+		})
+	}
+
+	formatted := make([]string, len(expressions))
+	for i, exp := range expressions {
+		formatted[i] = formatExpression(exp)
+	}
+
+	return strings.Join(formatted, " ")
+}
+
+func formatExpression(exp *internals.ExpressionNode) string {
+	args := make([]string, len(exp.Arguments))
+	for i, arg := range exp.Arguments {
+		args[i] = formatArgument(exp.ExpressionType, arg)
+	}
+
+	return canonicalExpressionNames[exp.ExpressionType] + "(" + strings.Join(args, ", ") + ")"
+}
+
+func formatArgument(expType internals.ExpressionType, arg *internals.ArgumentNode) string {
+	var sb strings.Builder
+	if arg.IsExclusion {
+		sb.WriteString("!")
+	}
+
+	if arg.IsFrom {
+		sb.WriteString("from ")
+	}
+
+	if arg.IsWildcard {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(formatValue(expType, arg.Range.Start))
+		if arg.Range.End != nil {
+			if arg.Range.IsHalfOpen {
+				sb.WriteString("..<")
+			} else {
+				sb.WriteString("..")
+			}
+
+			sb.WriteString(formatValue(expType, arg.Range.End))
+		}
+	}
+
+	if arg.HasNth() {
+		sb.WriteString("#" + strconv.Itoa(arg.NthValue()))
+	}
+
+	if arg.HasInterval() {
+		sb.WriteString("%" + strconv.Itoa(arg.IntervalValue()))
+	}
+
+	return sb.String()
+}
+
+func formatValue(expType internals.ExpressionType, value internals.ValueNode) string {
+	if date, ok := value.(*internals.DateValueNode); ok {
+		text := strconv.Itoa(date.Month) + "/" + strconv.Itoa(date.Day)
+		if date.HasYear {
+			text = strconv.Itoa(date.Year) + "/" + text
+		}
+
+		return text
+	}
+
+	if duration, ok := value.(*internals.DurationValueNode); ok {
+		return strconv.Itoa(duration.Value) + durationUnits[duration.Unit]
+	}
+
+	if calendar, ok := value.(*internals.CalendarValueNode); ok {
+		return calendar.Name
+	}
+
+	ival := value.(*internals.IntegerValueNode).Value
+	switch expType {
+	case internals.ExpressionTypeDaysOfWeek:
+		return dayLiterals[ival-1]
+	case internals.ExpressionTypeMonths:
+		return monthLiterals[ival-1]
+	}
+
+	return strconv.Itoa(ival)
+}
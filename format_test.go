@@ -0,0 +1,95 @@
+package schyntax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		schedule string
+		expected string
+	}{
+		{"minutesOfHour(*%5)", "min(*%5)"},
+		{"  days( MONDAY..friday ,!wed)hours(9..<17)", "dow(mon..fri, !wed) h(9..<17)"},
+		{"{dow(mon..fri) h(9..<17) min(*%15)}, dates(12/24..12/26) hourOfDay(0)", "dates(12/24..12/26) h(0), {dow(mon..fri) h(9..<17) min(*%15)}"},
+		{"{s(0)} {minute(30)},{}", "{s(0)}, {min(30)}"},
+		{"secondsOfMinute(00..10%2) dayOfMonth(-1) dayofyear(!-7..-1)", "s(0..10%2) dom(-1) doy(!-7..-1)"},
+		{"dates(2025/1/1..2025/03/01, !2/29) months(DECEMBER, 1..mar%2)", "dates(2025/1/1..2025/3/1, !2/29) months(dec, jan..mar%2)"},
+		{"dayofweek(Tues#2, fr#-1, 3%2)", "dow(tue#2, fri#-1, tue%2)"},
+	}
+
+	for _, test := range tests {
+		formatted, err := Format(test.schedule)
+		if err != nil {
+			t.Errorf("%q: %s", test.schedule, err)
+			continue
+		}
+
+		if formatted != test.expected {
+			t.Errorf("%q. Expected: %q, Actual: %q", test.schedule, test.expected, formatted)
+		}
+
+		assertSameIr(t, test.schedule, formatted)
+
+		// formatting is idempotent
+		if again, err := Format(formatted); err != nil || again != formatted {
+			t.Errorf("%q. Formatting again produced %q, %v", formatted, again, err)
+		}
+	}
+}
+
+func TestFormatSortExpressions(t *testing.T) {
+	schedule := "{dates(12/25) dow(mon) h(9) dom(1) min(0) months(dec) doy(100) s(5) h(10)}, dow(sat) m(1)"
+	expected := "min(1) dow(sat), {s(5) min(0) h(9) h(10) dow(mon) dom(1) doy(100) months(dec) dates(12/25)}"
+
+	formatted, err := Format(schedule, SortExpressions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if formatted != expected {
+		t.Errorf("Expected: %q, Actual: %q", expected, formatted)
+	}
+
+	assertSameIr(t, schedule, formatted)
+}
+
+func TestFormatErrors(t *testing.T) {
+	for _, schedule := range []string{"min(", "dow(8)", "hours(1..<1)"} {
+		_, err := Format(schedule)
+		if _, ok := err.(*internals.ParseError); !ok {
+			t.Errorf("%q: expected a *ParseError, got %v", schedule, err)
+		}
+	}
+}
+
+func TestFormatCronConversions(t *testing.T) {
+	for _, cron := range []string{"0 9 * * 1-5", "15 10 * JAN,jul Sun", "0 0 1,15 * 1", "30 */10 * * * *", "0 0 * * 5L"} {
+		conversion, err := ConvertCron(cron)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		formatted, err := Format(conversion.Schedule)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if formatted != conversion.Schedule {
+			t.Errorf("%q. Cron conversion %q is not canonical: %q", cron, conversion.Schedule, formatted)
+		}
+	}
+}
+
+func assertSameIr(t *testing.T, original, formatted string) {
+	t.Helper()
+
+	originalIr := internals.CompileAst(parse(original, nil))
+	formattedIr := internals.CompileAst(parse(formatted, nil))
+	if !reflect.DeepEqual(originalIr, formattedIr) {
+		t.Errorf("%q and %q compiled differently", original, formatted)
+	}
+}
@@ -8,9 +8,9 @@ import (
 	"fmt"
 )
 
-const _ExpressionType_name = "ExpressionTypeIntervalValueExpressionTypeSecondsExpressionTypeMinutesExpressionTypeHoursExpressionTypeDaysOfWeekExpressionTypeDaysOfMonthExpressionTypeDaysOfYearExpressionTypeDates"
+const _ExpressionType_name = "ExpressionTypeIntervalValueExpressionTypeNthValueExpressionTypeSecondsExpressionTypeMinutesExpressionTypeHoursExpressionTypeDaysOfWeekExpressionTypeDaysOfMonthExpressionTypeDaysOfYearExpressionTypeDatesExpressionTypeMonthsExpressionTypeMillisecondsExpressionTypeJitterExpressionTypeCalendarExpressionTypeYearsExpressionTypeWeeksExpressionTypeEveryExpressionTypeBusinessDays"
 
-var _ExpressionType_index = [...]uint8{0, 27, 48, 69, 88, 112, 137, 161, 180}
+var _ExpressionType_index = [...]uint16{0, 27, 49, 70, 91, 110, 134, 159, 183, 202, 222, 248, 268, 290, 309, 328, 347, 373}
 
 func (i ExpressionType) String() string {
 	i -= 1
@@ -2,6 +2,7 @@ package internals
 
 import (
 	"strconv"
+	"time"
 )
 
 func DaysInMonth(year, month int) int {
@@ -33,3 +34,15 @@ func DaysInYear(year int) int {
 func IsLeapYear(year int) bool {
 	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
 }
+
+// WeeksInISOYear returns the number of weeks (52 or 53) in an ISO 8601 week-numbering year.
+func WeeksInISOYear(year int) int {
+	// December 28 is always in the last week of its week-numbering year
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// DaysSinceEpoch returns the number of days from January 1, 1970 to the date.
+func DaysSinceEpoch(year, month, day int) int {
+	return int(time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60))
+}
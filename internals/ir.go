@@ -1,5 +1,7 @@
 package internals
 
+import "math/bits"
+
 /**********************************************************************************************
  * IrProgram
 **********************************************************************************************/
@@ -17,26 +19,149 @@ func NewIrProgram() *IrProgram {
 **********************************************************************************************/
 
 type IrGroup struct {
-	Seconds             []*IrIntegerRange
-	SecondsExcluded     []*IrIntegerRange
-	Minutes             []*IrIntegerRange
-	MinutesExcluded     []*IrIntegerRange
-	Hours               []*IrIntegerRange
-	HoursExcluded       []*IrIntegerRange
-	DaysOfWeek          []*IrIntegerRange
-	DaysOfWeekExcluded  []*IrIntegerRange
-	DaysOfMonth         []*IrIntegerRange
-	DaysOfMonthExcluded []*IrIntegerRange
-	DaysOfYear          []*IrIntegerRange
-	DaysOfYearExcluded  []*IrIntegerRange
-	Dates               []*IrDateRange
-	DatesExcluded       []*IrDateRange
+	Milliseconds         []*IrIntegerRange
+	MillisecondsExcluded []*IrIntegerRange
+	Seconds              []*IrIntegerRange
+	SecondsExcluded      []*IrIntegerRange
+	Minutes              []*IrIntegerRange
+	MinutesExcluded      []*IrIntegerRange
+	Hours                []*IrIntegerRange
+	HoursExcluded        []*IrIntegerRange
+	DaysOfWeek           []*IrIntegerRange
+	DaysOfWeekExcluded   []*IrIntegerRange
+	DaysOfMonth          []*IrIntegerRange
+	DaysOfMonthExcluded  []*IrIntegerRange
+	DaysOfYear           []*IrIntegerRange
+	DaysOfYearExcluded   []*IrIntegerRange
+	Months               []*IrIntegerRange
+	MonthsExcluded       []*IrIntegerRange
+	Dates                []*IrDateRange
+	DatesExcluded        []*IrDateRange
+	Weeks                []*IrIntegerRange // ISO 8601 weeks of the week-numbering year
+	WeeksExcluded        []*IrIntegerRange
+	Years                []*IrIntegerRange
+	YearsExcluded        []*IrIntegerRange
+	Periods              []*IrPeriod // the group matches a date if it's in any of the periods
+	BusinessDays         []*IrIntegerRange
+	BusinessDaysExcluded []*IrIntegerRange
+	Calendars            []string
+	CalendarsExcluded    []string
+	Jitter               int // the largest offset, in milliseconds, which can be added to each event; zero if none
+	// SmallestUnit is the smallest of milliseconds, seconds, minutes and hours which the group's expressions specify
+	// before implied rules are applied, or ExpressionTypeDaysOfMonth if they only specify days.
+	SmallestUnit ExpressionType
+
+	// Bitmasks of the applicable values of each unit, where bit n represents the value n. They are calculated from the
+	// ranges above by CompileMasks. The milliseconds, seconds, minutes, hours and months masks already account for
+	// exclusions.
+	MillisecondsMask       MillisecondsMask
+	SecondsMask            uint64
+	MinutesMask            uint64
+	HoursMask              uint32
+	MonthsMask             uint16
+	DaysOfWeekMask         uint8 // days of week which are included without the # operator
+	DaysOfWeekExcludedMask uint8 // days of week which are excluded without the # operator
+	HasDaysOfWeekNth       bool  // true if any included or excluded day of week uses the # operator
 }
 
 func NewIrGroup() *IrGroup {
 	return &IrGroup{}
 }
 
+// CompileMasks calculates the group's bitmasks from its ranges. It must be called again if the ranges are modified.
+func (ir *IrGroup) CompileMasks() {
+	ir.MillisecondsMask = compileMillisecondsMask(ir.Milliseconds, ir.MillisecondsExcluded)
+	ir.SecondsMask = compileMask(ir.Seconds, ir.SecondsExcluded, 0, 59)
+	ir.MinutesMask = compileMask(ir.Minutes, ir.MinutesExcluded, 0, 59)
+	ir.HoursMask = uint32(compileMask(ir.Hours, ir.HoursExcluded, 0, 23))
+	ir.MonthsMask = uint16(compileMask(ir.Months, ir.MonthsExcluded, 1, 12))
+
+	var included, excluded []*IrIntegerRange
+	ir.HasDaysOfWeekNth = false
+	for _, r := range ir.DaysOfWeek {
+		if r.HasNth {
+			ir.HasDaysOfWeekNth = true
+		} else {
+			included = append(included, r)
+		}
+	}
+
+	for _, r := range ir.DaysOfWeekExcluded {
+		if r.HasNth {
+			ir.HasDaysOfWeekNth = true
+		} else {
+			excluded = append(excluded, r)
+		}
+	}
+
+	// an empty list of ranges would mean every day, so only compile the lists which have something in them
+	ir.DaysOfWeekMask = 0
+	if !ir.HasDaysOfWeek() || len(included) > 0 {
+		ir.DaysOfWeekMask = uint8(compileMask(included, nil, 1, 7))
+	}
+
+	ir.DaysOfWeekExcludedMask = 0
+	if len(excluded) > 0 {
+		ir.DaysOfWeekExcludedMask = uint8(compileMask(excluded, nil, 1, 7))
+	}
+}
+
+// compileMask returns a bitmask of the values between min and max which are in any of the ranges (or every value if
+// there are no ranges), and not in any of the excluded ranges.
+func compileMask(ranges, excluded []*IrIntegerRange, min, max int) uint64 {
+	lengthOfUnit := max - min + 1
+	var mask uint64
+	for value := min; value <= max; value++ {
+		if len(ranges) > 0 && !rangesContain(ranges, value, lengthOfUnit) {
+			continue
+		}
+
+		if rangesContain(excluded, value, lengthOfUnit) {
+			continue
+		}
+
+		mask |= 1 << uint(value)
+	}
+
+	return mask
+}
+
+// compileMillisecondsMask is the same as compileMask, except that it covers the milliseconds from 0 to 999.
+func compileMillisecondsMask(ranges, excluded []*IrIntegerRange) MillisecondsMask {
+	var mask MillisecondsMask
+	for value := 0; value <= 999; value++ {
+		if len(ranges) > 0 && !rangesContain(ranges, value, 1000) {
+			continue
+		}
+
+		if rangesContain(excluded, value, 1000) {
+			continue
+		}
+
+		mask.Set(value)
+	}
+
+	return mask
+}
+
+func rangesContain(ranges []*IrIntegerRange, value, lengthOfUnit int) bool {
+	for _, r := range ranges {
+		if r.Contains(value, lengthOfUnit) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ir *IrGroup) HasMilliseconds() bool {
+	return len(ir.Milliseconds) > 0
+}
+
+func (ir *IrGroup) HasMillisecondsExcluded() bool {
+	return len(ir.MillisecondsExcluded) > 0
+}
+
 func (ir *IrGroup) HasSeconds() bool {
 	return len(ir.Seconds) > 0
 }
@@ -85,6 +210,14 @@ func (ir *IrGroup) HasDaysOfYearExcluded() bool {
 	return len(ir.DaysOfYearExcluded) > 0
 }
 
+func (ir *IrGroup) HasMonths() bool {
+	return len(ir.Months) > 0
+}
+
+func (ir *IrGroup) HasMonthsExcluded() bool {
+	return len(ir.MonthsExcluded) > 0
+}
+
 func (ir *IrGroup) HasDates() bool {
 	return len(ir.Dates) > 0
 }
@@ -93,6 +226,156 @@ func (ir *IrGroup) HasDatesExcluded() bool {
 	return len(ir.DatesExcluded) > 0
 }
 
+func (ir *IrGroup) HasWeeks() bool {
+	return len(ir.Weeks) > 0
+}
+
+func (ir *IrGroup) HasWeeksExcluded() bool {
+	return len(ir.WeeksExcluded) > 0
+}
+
+func (ir *IrGroup) HasYears() bool {
+	return len(ir.Years) > 0
+}
+
+func (ir *IrGroup) HasYearsExcluded() bool {
+	return len(ir.YearsExcluded) > 0
+}
+
+// IsApplicableYear returns true if the group's years expressions match year.
+func (ir *IrGroup) IsApplicableYear(year int) bool {
+	if ir.HasYears() && !rangesContain(ir.Years, year, 0) {
+		return false
+	}
+
+	return !rangesContain(ir.YearsExcluded, year, 0)
+}
+
+func (ir *IrGroup) HasPeriods() bool {
+	return len(ir.Periods) > 0
+}
+
+func (ir *IrGroup) HasBusinessDays() bool {
+	return len(ir.BusinessDays) > 0
+}
+
+func (ir *IrGroup) HasBusinessDaysExcluded() bool {
+	return len(ir.BusinessDaysExcluded) > 0
+}
+
+func (ir *IrGroup) HasCalendars() bool {
+	return len(ir.Calendars) > 0
+}
+
+func (ir *IrGroup) HasCalendarsExcluded() bool {
+	return len(ir.CalendarsExcluded) > 0
+}
+
+/**********************************************************************************************
+ * MillisecondsMask
+**********************************************************************************************/
+
+// MillisecondsMask is a bitmask of the milliseconds from 0 to 999, where bit n represents millisecond n.
+type MillisecondsMask [16]uint64
+
+// ZeroMilliseconds is the mask of a group which only fires at millisecond zero, which is the case for every group
+// without a milliseconds expression.
+var ZeroMilliseconds = MillisecondsMask{1}
+
+func (m *MillisecondsMask) Set(value int) {
+	m[value/64] |= 1 << uint(value%64)
+}
+
+func (m MillisecondsMask) Has(value int) bool {
+	return m[value/64]&(1<<uint(value%64)) != 0
+}
+
+func (m MillisecondsMask) IsEmpty() bool {
+	return m == MillisecondsMask{}
+}
+
+// And returns the milliseconds which are in both m and other.
+func (m MillisecondsMask) And(other MillisecondsMask) MillisecondsMask {
+	for i := range m {
+		m[i] &= other[i]
+	}
+
+	return m
+}
+
+// Or returns the milliseconds which are in either m or other.
+func (m MillisecondsMask) Or(other MillisecondsMask) MillisecondsMask {
+	for i := range m {
+		m[i] |= other[i]
+	}
+
+	return m
+}
+
+// AndNot returns the milliseconds which are in m, but not in other.
+func (m MillisecondsMask) AndNot(other MillisecondsMask) MillisecondsMask {
+	for i := range m {
+		m[i] &^= other[i]
+	}
+
+	return m
+}
+
+// Values returns the milliseconds in the mask in ascending order.
+func (m MillisecondsMask) Values() []int {
+	var values []int
+	for v := m.Next(0, true); v != -1; v = m.Next(v+1, true) {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// Next returns the first millisecond in the mask which is at or after from, or at or before from when forward is false.
+// Returns -1 if there is no such millisecond.
+func (m MillisecondsMask) Next(from int, forward bool) int {
+	if forward {
+		if from < 0 {
+			from = 0
+		}
+
+		for w := from / 64; w < len(m); w++ {
+			word := m[w]
+			if w == from/64 {
+				word &^= 1<<uint(from%64) - 1 // ignore the bits before from
+			}
+
+			if word != 0 {
+				return w*64 + bits.TrailingZeros64(word)
+			}
+		}
+
+		return -1
+	}
+
+	if from < 0 {
+		return -1
+	}
+
+	if from > 999 {
+		from = 999
+	}
+
+	for w := from / 64; w >= 0; w-- {
+		word := m[w]
+		if w == from/64 {
+			shift := uint(63 - from%64) // ignore the bits after from
+			word = word << shift >> shift
+		}
+
+		if word != 0 {
+			return w*64 + 63 - bits.LeadingZeros64(word)
+		}
+	}
+
+	return -1
+}
+
 /**********************************************************************************************
  * IrIntegerRange
 **********************************************************************************************/
@@ -105,6 +388,9 @@ type IrIntegerRange struct {
 	End         int
 	Interval    int
 	HasInterval bool
+	Nth         int // only used by days of week: the occurrence of the day within the month (negative counts from the end)
+	HasNth      bool
+	From        int // only used by business days: the day of the month they count from, or zero for the start and end
 }
 
 func NewIrIntegerRange(start, end int, hasEnd bool, interval int, isSplit, isHalfOpen bool) *IrIntegerRange {
@@ -135,6 +421,43 @@ func (ir IrIntegerRange) CloneWithRevisedRange(start, end int) *IrIntegerRange {
 	return &ir
 }
 
+// Contains returns true if value is in the range. lengthOfUnit is the number of possible values of the unit, which is
+// used to calculate intervals for split ranges. Negative values must already be converted to their positive equivalent.
+func (ir *IrIntegerRange) Contains(value, lengthOfUnit int) bool {
+	if !ir.IsRange {
+		return value == ir.Start
+	}
+
+	if ir.IsHalfOpen && value == ir.End {
+		return false
+	}
+
+	if ir.IsSplit { // range spans across the max value and loops back around
+		if value <= ir.End || value >= ir.Start {
+			if ir.HasInterval {
+				if value >= ir.Start {
+					return (value-ir.Start)%ir.Interval == 0
+				}
+
+				return (value+lengthOfUnit-ir.Start)%ir.Interval == 0
+			}
+
+			return true
+		}
+
+	} else { // not a split range (easier case)
+		if value >= ir.Start && value <= ir.End {
+			if ir.HasInterval {
+				return (value-ir.Start)%ir.Interval == 0
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
 /**********************************************************************************************
  * IrDateRange
 **********************************************************************************************/
@@ -187,3 +510,24 @@ func NewIrDate(year, month, day int, hasYear bool) *IrDate {
 
 	return ir
 }
+
+/**********************************************************************************************
+ * IrPeriod
+**********************************************************************************************/
+
+// IrPeriod matches every Days days, counting continuously from the date From, which always has a year. Dates before
+// From don't match.
+type IrPeriod struct {
+	Days int
+	From *IrDate
+}
+
+func NewIrPeriod(days int, from *IrDate) *IrPeriod {
+	return &IrPeriod{days, from}
+}
+
+// Contains returns true if the date is a whole number of periods on or after From.
+func (ir *IrPeriod) Contains(year, month, day int) bool {
+	days := DaysSinceEpoch(year, month, day) - DaysSinceEpoch(ir.From.Year, ir.From.Month, ir.From.Day)
+	return days >= 0 && days%ir.Days == 0
+}
@@ -4,14 +4,14 @@ func CompileAst(program *ProgramNode) *IrProgram {
 	ir := NewIrProgram()
 
 	// free-floating expressions are placed in an implicit group
-	irGroup := compileGroup(program.Expressions)
+	irGroup := CompileGroup(program.Expressions)
 	if irGroup != nil {
 		ir.Groups = append(ir.Groups, irGroup)
 	}
 
 	// compile all groups
 	for _, groupNode := range program.Groups {
-		irGroup = compileGroup(groupNode.Expressions)
+		irGroup = CompileGroup(groupNode.Expressions)
 		if irGroup != nil {
 			ir.Groups = append(ir.Groups, irGroup)
 		}
@@ -20,7 +20,9 @@ func CompileAst(program *ProgramNode) *IrProgram {
 	return ir
 }
 
-func compileGroup(expressions []*ExpressionNode) *IrGroup {
+// CompileGroup compiles a list of expressions, which are treated as a single group, including any implied rules.
+// Returns nil if there are no expressions.
+func CompileGroup(expressions []*ExpressionNode) *IrGroup {
 	if len(expressions) == 0 {
 		return nil
 	}
@@ -31,8 +33,27 @@ func compileGroup(expressions []*ExpressionNode) *IrGroup {
 		compileExpression(irGroup, expression)
 	}
 
-	// setup implied rules
-	if !irGroup.HasSeconds() && !irGroup.HasSecondsExcluded() { // don't need to setup any defaults if seconds are defined
+	switch {
+	case irGroup.HasMilliseconds() || irGroup.HasMillisecondsExcluded():
+		irGroup.SmallestUnit = ExpressionTypeMilliseconds
+	case irGroup.HasSeconds() || irGroup.HasSecondsExcluded():
+		irGroup.SmallestUnit = ExpressionTypeSeconds
+	case irGroup.HasMinutes() || irGroup.HasMinutesExcluded():
+		irGroup.SmallestUnit = ExpressionTypeMinutes
+	case irGroup.HasHours() || irGroup.HasHoursExcluded():
+		irGroup.SmallestUnit = ExpressionTypeHours
+	default:
+		irGroup.SmallestUnit = ExpressionTypeDaysOfMonth
+	}
+
+	// setup implied rules. Milliseconds are zero unless they're defined, in which case the units above them don't need
+	// any defaults.
+	hasMilliseconds := irGroup.HasMilliseconds() || irGroup.HasMillisecondsExcluded()
+	if !hasMilliseconds {
+		irGroup.Milliseconds = append(irGroup.Milliseconds, getZeroInteger())
+	}
+
+	if !hasMilliseconds && !irGroup.HasSeconds() && !irGroup.HasSecondsExcluded() { // don't need to setup any defaults if seconds are defined
 		if irGroup.HasMinutes() || irGroup.HasMinutesExcluded() {
 			irGroup.Seconds = append(irGroup.Seconds, getZeroInteger())
 		} else if irGroup.HasHours() || irGroup.HasHoursExcluded() {
@@ -45,12 +66,34 @@ func compileGroup(expressions []*ExpressionNode) *IrGroup {
 		}
 	}
 
+	irGroup.CompileMasks()
+
 	return irGroup
 }
 
 func compileExpression(irGroup *IrGroup, expression *ExpressionNode) {
+	if expression.ExpressionType == ExpressionTypeEvery {
+		// the validator has made sure the period is followed by the from date
+		period := expression.Arguments[0].Range.Start.(*DurationValueNode)
+		from := expression.Arguments[1].Range.Start.(*DateValueNode)
+		days := period.Milliseconds() / (24 * 60 * 60 * 1000)
+		irGroup.Periods = append(irGroup.Periods, NewIrPeriod(days, NewIrDate(from.Year, from.Month, from.Day, true)))
+		return
+	}
+
 	for _, arg := range expression.Arguments {
 		switch expression.ExpressionType {
+		case ExpressionTypeCalendar:
+			name := arg.Range.Start.(*CalendarValueNode).Name
+			if arg.IsExclusion {
+				irGroup.CalendarsExcluded = append(irGroup.CalendarsExcluded, name)
+			} else {
+				irGroup.Calendars = append(irGroup.Calendars, name)
+			}
+		case ExpressionTypeJitter:
+			irGroup.Jitter = arg.Range.Start.(*DurationValueNode).Milliseconds()
+		case ExpressionTypeMilliseconds:
+			compileMillisecondsArgument(irGroup, arg)
 		case ExpressionTypeSeconds:
 			compileSecondsArgument(irGroup, arg)
 		case ExpressionTypeMinutes:
@@ -63,8 +106,18 @@ func compileExpression(irGroup *IrGroup, expression *ExpressionNode) {
 			compileDaysOfMonthArgument(irGroup, arg)
 		case ExpressionTypeDaysOfYear:
 			compileDaysOfYearArgument(irGroup, arg)
+		case ExpressionTypeMonths:
+			compileMonthsArgument(irGroup, arg)
 		case ExpressionTypeDates:
 			compileDateArgument(irGroup, arg)
+		case ExpressionTypeWeeks:
+			compileWeeksArgument(irGroup, arg)
+		case ExpressionTypeYears:
+			compileYearsArgument(irGroup, arg)
+		case ExpressionTypeBusinessDays:
+			if !arg.IsFrom {
+				compileBusinessDaysArgument(irGroup, arg, businessDaysFrom(expression))
+			}
 		default:
 			panic("Expression type " + expression.ExpressionType.Name() + " not supported by the schyntax compiler.")
 		}
@@ -92,7 +145,7 @@ func compileDateArgument(irGroup *IrGroup, arg *ArgumentNode) {
 		}
 
 		// check for split range (spans January 1) - not applicable for dates with explicit years
-		if irEnd != nil && start.HasYear {
+		if irEnd != nil && !start.HasYear {
 			if irStart.Month >= irEnd.Month && (irStart.Month > irEnd.Month || irStart.Day > irEnd.Day) {
 				isSplit = true
 			}
@@ -114,6 +167,15 @@ func compileDateArgument(irGroup *IrGroup, arg *ArgumentNode) {
 	}
 }
 
+func compileMillisecondsArgument(irGroup *IrGroup, arg *ArgumentNode) {
+	irArg := compileIntegerArgument(arg, 0, 999)
+	if arg.IsExclusion {
+		irGroup.MillisecondsExcluded = append(irGroup.MillisecondsExcluded, irArg)
+	} else {
+		irGroup.Milliseconds = append(irGroup.Milliseconds, irArg)
+	}
+}
+
 func compileSecondsArgument(irGroup *IrGroup, arg *ArgumentNode) {
 	irArg := compileIntegerArgument(arg, 0, 59)
 	if arg.IsExclusion {
@@ -143,6 +205,11 @@ func compileHoursArgument(irGroup *IrGroup, arg *ArgumentNode) {
 
 func compileDaysOfWeekArgument(irGroup *IrGroup, arg *ArgumentNode) {
 	irArg := compileIntegerArgument(arg, 1, 7)
+	if arg.HasNth() {
+		irArg.Nth = arg.NthValue()
+		irArg.HasNth = true
+	}
+
 	if arg.IsExclusion {
 		irGroup.DaysOfWeekExcluded = append(irGroup.DaysOfWeekExcluded, irArg)
 	} else {
@@ -168,6 +235,54 @@ func compileDaysOfYearArgument(irGroup *IrGroup, arg *ArgumentNode) {
 	}
 }
 
+func compileMonthsArgument(irGroup *IrGroup, arg *ArgumentNode) {
+	irArg := compileIntegerArgument(arg, 1, 12)
+	if arg.IsExclusion {
+		irGroup.MonthsExcluded = append(irGroup.MonthsExcluded, irArg)
+	} else {
+		irGroup.Months = append(irGroup.Months, irArg)
+	}
+}
+
+func compileWeeksArgument(irGroup *IrGroup, arg *ArgumentNode) {
+	irArg := compileIntegerArgument(arg, 1, 53)
+	if arg.IsExclusion {
+		irGroup.WeeksExcluded = append(irGroup.WeeksExcluded, irArg)
+	} else {
+		irGroup.Weeks = append(irGroup.Weeks, irArg)
+	}
+}
+
+func compileYearsArgument(irGroup *IrGroup, arg *ArgumentNode) {
+	irArg := compileIntegerArgument(arg, 1900, 2200)
+	if arg.IsExclusion {
+		irGroup.YearsExcluded = append(irGroup.YearsExcluded, irArg)
+	} else {
+		irGroup.Years = append(irGroup.Years, irArg)
+	}
+}
+
+func compileBusinessDaysArgument(irGroup *IrGroup, arg *ArgumentNode, from int) {
+	irArg := compileIntegerArgument(arg, 1, 31)
+	irArg.From = from
+	if arg.IsExclusion {
+		irGroup.BusinessDaysExcluded = append(irGroup.BusinessDaysExcluded, irArg)
+	} else {
+		irGroup.BusinessDays = append(irGroup.BusinessDays, irArg)
+	}
+}
+
+// businessDaysFrom returns the value of a businessdays expression's from argument, or zero if it doesn't have one. The
+// validator has made sure that it's the last argument.
+func businessDaysFrom(expression *ExpressionNode) int {
+	last := expression.Arguments[len(expression.Arguments)-1]
+	if !last.IsFrom {
+		return 0
+	}
+
+	return last.Range.Start.(*IntegerValueNode).Value
+}
+
 func compileIntegerArgument(arg *ArgumentNode, wildStart, wildEnd int) *IrIntegerRange {
 	start := 0
 	end := 0
@@ -17,13 +17,15 @@ const (
 type lexMethod func() lexMethod
 
 type Lexer struct {
-	contextStack  []ContextMode
-	input         string
-	index         int
-	length        int
-	leadingTrivia string
-	tokenQueue    TokenQueue
-	lexMethod     lexMethod
+	contextStack   []ContextMode
+	input          string
+	index          int
+	length         int
+	leadingTrivia  string
+	tokenQueue     TokenQueue
+	lexMethod      lexMethod
+	errors         *ParseErrorList // if not nil, errors are added to the list instead of stopping the lexer
+	expressionType ExpressionType  // the type of the expression whose arguments are being lexed
 }
 
 func NewLexer(input string) *Lexer {
@@ -59,7 +61,69 @@ func (l *Lexer) Peek() *Token {
 func (l *Lexer) queueNext() {
 	for l.tokenQueue.IsEmpty() {
 		l.consumeWhiteSpace()
-		l.lexMethod = l.lexMethod()
+		if l.errors == nil {
+			l.lexMethod = l.lexMethod()
+		} else {
+			l.lexMethod = l.lexRecovering()
+		}
+	}
+}
+
+// lexRecovering calls the current lex method. If it fails, the error is recorded, and the input up to the next point
+// where lexing can resume is consumed as an Error token.
+func (l *Lexer) lexRecovering() (next lexMethod) {
+	defer func() {
+		if e := recover(); e != nil {
+			err, ok := e.(*ParseError)
+			if !ok {
+				panic(e)
+			}
+
+			l.errors.Add(err)
+			next = l.resync()
+		}
+	}()
+
+	return l.lexMethod()
+}
+
+func (l *Lexer) resync() lexMethod {
+	if l.isEndNext() {
+		// unexpected end of input, so close every open group and expression
+		l.contextStack = l.contextStack[:1]
+		return l.lexList
+	}
+
+	end := l.index
+	for end < l.length && !l.isSyncCharacter(end) {
+		end++
+	}
+
+	tok := &Token{}
+	tok.Type = TokenTypeError
+	tok.Index = l.index
+	tok.RawValue = l.input[l.index:end]
+	tok.Value = tok.RawValue
+	l.consumeToken(tok)
+
+	if l.context() == ContextModeExpression && !l.isEndNext() && l.input[l.index] == '}' {
+		// the expression was never closed, so let the group close instead
+		l.exitContext()
+	}
+
+	return l.lexList
+}
+
+// isSyncCharacter returns true if lexing can resume at index in the current context.
+func (l *Lexer) isSyncCharacter(index int) bool {
+	c := l.input[index]
+	switch l.context() {
+	case ContextModeExpression:
+		return c == ',' || c == ')' || c == '}'
+	case ContextModeGroup:
+		return c == ',' || c == '}' || s_whitespaceRegex.MatchString(l.input[index:])
+	default:
+		return c == ',' || c == '{' || s_whitespaceRegex.MatchString(l.input[index:])
 	}
 }
 
@@ -208,20 +272,33 @@ func (l *Lexer) lexGroup() lexMethod {
 	return l.lexList
 }
 
+var expressionNameTerms = []*Terminal{
+	TermsMilliseconds,
+	TermsSeconds,
+	TermsMinutes,
+	TermsHours,
+	TermsDaysOfWeek,
+	TermsDaysOfMonth,
+	TermsDaysOfYear,
+	TermsBusinessDays,
+	TermsWeeks,
+	TermsMonths,
+	TermsDates,
+	TermsYears,
+	TermsEvery,
+	TermsJitter,
+	TermsCalendar,
+}
+
 func (l *Lexer) lexExpression() lexMethod {
-	consumedExpName := l.consumeOptionalTerm(TermsSeconds) ||
-		l.consumeOptionalTerm(TermsMinutes) ||
-		l.consumeOptionalTerm(TermsHours) ||
-		l.consumeOptionalTerm(TermsDaysOfWeek) ||
-		l.consumeOptionalTerm(TermsDaysOfMonth) ||
-		l.consumeOptionalTerm(TermsDaysOfYear) ||
-		l.consumeOptionalTerm(TermsDates)
-
-	if consumedExpName {
-		l.consumeTerm(TermsOpenParen)
-		l.enterContext(ContextModeExpression)
+	for _, term := range expressionNameTerms {
+		if l.consumeOptionalTerm(term) {
+			l.expressionType = term.ExpressionType
+			l.consumeTerm(TermsOpenParen)
+			l.enterContext(ContextModeExpression)
 
-		return l.lexList
+			return l.lexList
+		}
 	}
 
 	panic(l.unexpectedText(TokenTypeExpressionName))
@@ -230,6 +307,16 @@ func (l *Lexer) lexExpression() lexMethod {
 func (l *Lexer) lexExpressionArgument() lexMethod {
 	l.consumeOptionalTerm(TermsNot)
 
+	if l.expressionType == ExpressionTypeCalendar {
+		// calendar names could be mistaken for day or month literals, so they're only looked for in calendar expressions
+		l.consumeTerm(TermsCalendarName)
+		return l.lexList
+	}
+
+	if l.expressionType == ExpressionTypeEvery || l.expressionType == ExpressionTypeBusinessDays {
+		l.consumeOptionalTerm(TermsFrom)
+	}
+
 	if !l.consumeOptionalTerm(TermsWildcard) {
 		l.consumeNumberDayOrDate()
 
@@ -239,6 +326,12 @@ func (l *Lexer) lexExpressionArgument() lexMethod {
 		}
 	}
 
+	if l.consumeOptionalTerm(TermsNth) {
+		if !l.consumeOptionalTerm(TermsPositiveInteger) && !l.consumeOptionalTerm(TermsNegativeInteger) {
+			panic(l.unexpectedText(TokenTypePositiveInteger, TokenTypeNegativeInteger))
+		}
+	}
+
 	if l.consumeOptionalTerm(TermsInterval) {
 		l.consumeTerm(TermsPositiveInteger)
 	}
@@ -256,6 +349,9 @@ func (l *Lexer) consumeNumberDayOrDate() {
 			if l.consumeOptionalTerm(TermsForwardSlash) {
 				l.consumeTerm(TermsPositiveInteger)
 			}
+		} else {
+			// or a duration
+			l.consumeOptionalDurationUnit()
 		}
 
 		return
@@ -268,9 +364,30 @@ func (l *Lexer) consumeNumberDayOrDate() {
 		l.consumeOptionalTerm(TermsWednesday) ||
 		l.consumeOptionalTerm(TermsThursday) ||
 		l.consumeOptionalTerm(TermsFriday) ||
-		l.consumeOptionalTerm(TermsSaturday) {
+		l.consumeOptionalTerm(TermsSaturday) ||
+		l.consumeOptionalTerm(TermsJanuary) ||
+		l.consumeOptionalTerm(TermsFebruary) ||
+		l.consumeOptionalTerm(TermsMarch) ||
+		l.consumeOptionalTerm(TermsApril) ||
+		l.consumeOptionalTerm(TermsMay) ||
+		l.consumeOptionalTerm(TermsJune) ||
+		l.consumeOptionalTerm(TermsJuly) ||
+		l.consumeOptionalTerm(TermsAugust) ||
+		l.consumeOptionalTerm(TermsSeptember) ||
+		l.consumeOptionalTerm(TermsOctober) ||
+		l.consumeOptionalTerm(TermsNovember) ||
+		l.consumeOptionalTerm(TermsDecember) {
 		return
 	}
 
-	panic(l.unexpectedText(TokenTypePositiveInteger, TokenTypeNegativeInteger, TokenTypeDayLiteral))
+	panic(l.unexpectedText(TokenTypePositiveInteger, TokenTypeNegativeInteger, TokenTypeDayLiteral, TokenTypeMonthLiteral))
+}
+
+func (l *Lexer) consumeOptionalDurationUnit() bool {
+	return l.consumeOptionalTerm(TermsDurationMilliseconds) ||
+		l.consumeOptionalTerm(TermsDurationSeconds) ||
+		l.consumeOptionalTerm(TermsDurationMinutes) ||
+		l.consumeOptionalTerm(TermsDurationHours) ||
+		l.consumeOptionalTerm(TermsDurationDays) ||
+		l.consumeOptionalTerm(TermsDurationWeeks)
 }
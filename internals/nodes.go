@@ -5,6 +5,7 @@ type ExpressionType int
 
 const (
 	ExpressionTypeIntervalValue ExpressionType = iota + 1 // used internally by the parser (not a real expression type)
+	ExpressionTypeNthValue                                // used internally by the parser (not a real expression type)
 	ExpressionTypeSeconds
 	ExpressionTypeMinutes
 	ExpressionTypeHours
@@ -12,6 +13,14 @@ const (
 	ExpressionTypeDaysOfMonth
 	ExpressionTypeDaysOfYear
 	ExpressionTypeDates
+	ExpressionTypeMonths
+	ExpressionTypeMilliseconds
+	ExpressionTypeJitter
+	ExpressionTypeCalendar
+	ExpressionTypeYears
+	ExpressionTypeWeeks
+	ExpressionTypeEvery
+	ExpressionTypeBusinessDays
 )
 
 var s_expressionTypeLen int = len("ExpressionType")
@@ -79,6 +88,7 @@ func (n *GroupNode) AddExpression(exp *ExpressionNode) {
 type ExpressionNode struct {
 	NodeBase
 	ExpressionType ExpressionType
+	NameToken      *Token
 	Arguments      []*ArgumentNode
 }
 
@@ -96,6 +106,17 @@ type ArgumentNode struct {
 	Interval    *IntegerValueNode
 	IsWildcard  bool
 	Range       *RangeNode
+	Nth         *IntegerValueNode
+	IsFrom      bool // true if the argument is preceded by "from", which is only valid in every and businessdays expressions
+}
+
+// Index returns the index of the argument's first token, which may belong to its range.
+func (n *ArgumentNode) Index() int {
+	if n.Range != nil && n.Range.Start != nil && (len(n.Tokens) == 0 || n.Range.Start.Index() < n.Tokens[0].Index) {
+		return n.Range.Start.Index()
+	}
+
+	return n.NodeBase.Index()
 }
 
 func (n *ArgumentNode) HasInterval() bool {
@@ -106,6 +127,14 @@ func (n *ArgumentNode) IntervalValue() int {
 	return n.Interval.Value
 }
 
+func (n *ArgumentNode) HasNth() bool {
+	return n.Nth != nil
+}
+
+func (n *ArgumentNode) NthValue() int {
+	return n.Nth.Value
+}
+
 func (n *ArgumentNode) IsRange() bool {
 	return n.Range != nil && n.Range.End != nil
 }
@@ -128,6 +157,16 @@ func (n *ArgumentNode) IntervalTokenIndex() int {
 	panic(`IntervalTokenIndex called, but no there are no Interval tokens on this node.`)
 }
 
+func (n *ArgumentNode) NthTokenIndex() int {
+	for _, tok := range n.Tokens {
+		if tok.Type == TokenTypeNth {
+			return tok.Index
+		}
+	}
+
+	panic(`NthTokenIndex called, but no there are no Nth tokens on this node.`)
+}
+
 /**********************************************************************************************
  * Range
 **********************************************************************************************/
@@ -148,6 +187,8 @@ type ValueNodeType int8
 const (
 	IntegerValueType ValueNodeType = iota
 	DateValueType
+	DurationValueType
+	CalendarValueType
 )
 
 type ValueNode interface {
@@ -187,3 +228,54 @@ type DateValueNode struct {
 func (n *DateValueNode) ValueNodeType() ValueNodeType {
 	return DateValueType
 }
+
+/**********************************************************************************************
+ * DurationValue
+**********************************************************************************************/
+
+var _ ValueNode = &DurationValueNode{}
+
+type DurationValueNode struct {
+	NodeBase
+	Value int
+	Unit  string // the value of the unit's token: MS, S, MIN or H
+}
+
+func (n *DurationValueNode) ValueNodeType() ValueNodeType {
+	return DurationValueType
+}
+
+// Milliseconds returns the length of the duration in milliseconds.
+func (n *DurationValueNode) Milliseconds() int {
+	switch n.Unit {
+	case "MS":
+		return n.Value
+	case "S":
+		return n.Value * 1000
+	case "MIN":
+		return n.Value * 60 * 1000
+	case "H":
+		return n.Value * 60 * 60 * 1000
+	case "D":
+		return n.Value * 24 * 60 * 60 * 1000
+	case "W":
+		return n.Value * 7 * 24 * 60 * 60 * 1000
+	default:
+		panic(n.Unit + " is not a duration unit.")
+	}
+}
+
+/**********************************************************************************************
+ * CalendarValue
+**********************************************************************************************/
+
+var _ ValueNode = &CalendarValueNode{}
+
+type CalendarValueNode struct {
+	NodeBase
+	Name string // always lower case, since calendar names are case-insensitive
+}
+
+func (n *CalendarValueNode) ValueNodeType() ValueNodeType {
+	return CalendarValueType
+}
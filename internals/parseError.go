@@ -1,8 +1,12 @@
 package internals
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
 
 var _ error = &ParseError{}
+var _ error = &ParseErrorList{}
 
 type ParseError struct {
 	message string
@@ -27,6 +31,63 @@ func (e *ParseError) Index() int {
 	return e.index
 }
 
+// ParseErrorList is every error found in an input by a lexer, parser and validator which are recovering from errors,
+// ordered by index. Only the first error at each index is kept.
+type ParseErrorList struct {
+	input  string
+	errors []*ParseError
+}
+
+func NewParseErrorList(input string) *ParseErrorList {
+	return &ParseErrorList{input: input}
+}
+
+// Add adds err to the list, unless there is already an error at the same index.
+func (l *ParseErrorList) Add(err *ParseError) {
+	i := sort.Search(len(l.errors), func(i int) bool {
+		return l.errors[i].index >= err.index
+	})
+
+	if i < len(l.errors) && l.errors[i].index == err.index {
+		return
+	}
+
+	l.errors = append(l.errors, nil)
+	copy(l.errors[i+1:], l.errors[i:])
+	l.errors[i] = err
+}
+
+func (l *ParseErrorList) Len() int {
+	return len(l.errors)
+}
+
+// Errors returns the errors in order of their index.
+func (l *ParseErrorList) Errors() []*ParseError {
+	return l.errors
+}
+
+func (l *ParseErrorList) Error() string {
+	messages := make([]string, len(l.errors))
+	for i, err := range l.errors {
+		messages[i] = err.message
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (l *ParseErrorList) Input() string {
+	return l.input
+}
+
+// Index returns the index of the first error.
+func (l *ParseErrorList) Index() int {
+	if len(l.errors) == 0 {
+		return 0
+	}
+
+	return l.errors[0].index
+}
+
 func getStringSnippet(input string, index int) string {
 	before := []rune(input[0:index])
 	after := []rune(input[index:])
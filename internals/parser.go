@@ -2,10 +2,12 @@ package internals
 
 import (
 	"strconv"
+	"strings"
 )
 
 type Parser struct {
-	lexer *Lexer
+	lexer  *Lexer
+	errors *ParseErrorList
 }
 
 func NewParser(input string) *Parser {
@@ -14,6 +16,16 @@ func NewParser(input string) *Parser {
 	return p
 }
 
+// NewRecoveringParser creates a parser which adds lexical and syntax errors to errors, rather than stopping at the first
+// one. Arguments, expressions and groups which contain errors are skipped, so the program it returns only contains
+// nodes which were parsed successfully.
+func NewRecoveringParser(input string, errors *ParseErrorList) *Parser {
+	p := NewParser(input)
+	p.errors = errors
+	p.lexer.errors = errors
+	return p
+}
+
 func (p *Parser) Input() string {
 	return p.lexer.input
 }
@@ -38,7 +50,7 @@ func (p *Parser) isNext(tokType TokenType) bool {
 	return p.peek().Type == tokType
 }
 
-func (p *Parser) wrongToken(expectedTokenTypes ...TokenType) error {
+func (p *Parser) wrongToken(expectedTokenTypes ...TokenType) *ParseError {
 	next := p.peek()
 
 	msg := `Unexpected token type ` + next.Type.Name() + ` at index ` + strconv.Itoa(next.Index) + `. Was expecting `
@@ -57,6 +69,43 @@ func (p *Parser) wrongToken(expectedTokenTypes ...TokenType) error {
 	return newParseError(msg, p.Input(), next.Index)
 }
 
+// tryParse calls parse. If the parser is recovering from errors, a ParseError from parse is recorded, and tokens are
+// skipped until one of syncTypes (or the end of input) is next. Returns false if there was an error.
+func (p *Parser) tryParse(parse func(), syncTypes ...TokenType) (ok bool) {
+	if p.errors == nil {
+		parse()
+		return true
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			err, isParseError := e.(*ParseError)
+			if !isParseError {
+				panic(e)
+			}
+
+			p.errors.Add(err)
+			p.skipUntil(syncTypes...)
+			ok = false
+		}
+	}()
+
+	parse()
+	return true
+}
+
+func (p *Parser) skipUntil(tokenTypes ...TokenType) {
+	for !p.isNext(TokenTypeEndOfInput) {
+		for _, tokType := range tokenTypes {
+			if p.isNext(tokType) {
+				return
+			}
+		}
+
+		p.advance()
+	}
+}
+
 func (p *Parser) Parse() *ProgramNode {
 	return p.parseProgram()
 }
@@ -65,13 +114,17 @@ func (p *Parser) parseProgram() *ProgramNode {
 	program := &ProgramNode{}
 
 	for !p.isNext(TokenTypeEndOfInput) {
-		if p.isNext(TokenTypeOpenCurly) {
-			program.AddGroup(p.parseGroup())
-		} else if p.isNext(TokenTypeExpressionName) {
-			program.AddExpression(p.parseExpression())
-		} else {
-			panic(p.wrongToken(TokenTypeOpenCurly, TokenTypeExpressionName, TokenTypeComma))
-		}
+		p.tryParse(func() {
+			if p.isNext(TokenTypeOpenCurly) {
+				program.AddGroup(p.parseGroup())
+			} else if p.isNext(TokenTypeExpressionName) {
+				if exp := p.parseExpression(); exp != nil {
+					program.AddExpression(exp)
+				}
+			} else {
+				panic(p.wrongToken(TokenTypeOpenCurly, TokenTypeExpressionName, TokenTypeComma))
+			}
+		}, TokenTypeOpenCurly, TokenTypeExpressionName)
 
 		if p.isNext(TokenTypeComma) { // optional comma
 			program.AddToken(p.advance())
@@ -87,7 +140,17 @@ func (p *Parser) parseGroup() *GroupNode {
 	group.AddToken(p.expect(TokenTypeOpenCurly))
 
 	for !p.isNext(TokenTypeCloseCurly) {
-		group.AddExpression(p.parseExpression())
+		if p.errors != nil && (p.isNext(TokenTypeEndOfInput) || p.isNext(TokenTypeOpenCurly)) {
+			// the group was never closed
+			p.errors.Add(p.wrongToken(TokenTypeCloseCurly))
+			return group
+		}
+
+		p.tryParse(func() {
+			if exp := p.parseExpression(); exp != nil {
+				group.AddExpression(exp)
+			}
+		}, TokenTypeExpressionName, TokenTypeCloseCurly, TokenTypeOpenCurly)
 
 		if p.isNext(TokenTypeComma) {
 			group.AddToken(p.advance())
@@ -103,10 +166,23 @@ func (p *Parser) parseExpression() *ExpressionNode {
 	expType := nameTok.ExpressionType
 	exp := &ExpressionNode{}
 	exp.ExpressionType = expType
+	exp.NameToken = nameTok
 	exp.AddToken(p.expect(TokenTypeOpenParen))
 
+	hadError := false
 	for {
-		exp.AddArgument(p.parseArgument(expType))
+		ok := p.tryParse(func() {
+			exp.AddArgument(p.parseArgument(expType))
+		}, TokenTypeComma, TokenTypeCloseParen, TokenTypeCloseCurly, TokenTypeOpenCurly)
+
+		if !ok {
+			hadError = true
+			if !p.isNext(TokenTypeComma) && !p.isNext(TokenTypeCloseParen) {
+				// the expression was never closed
+				p.errors.Add(p.wrongToken(TokenTypeCloseParen))
+				break
+			}
+		}
 
 		if p.isNext(TokenTypeComma) {
 			exp.AddToken(p.advance())
@@ -117,6 +193,18 @@ func (p *Parser) parseExpression() *ExpressionNode {
 		}
 	}
 
+	if hadError {
+		if p.isNext(TokenTypeCloseParen) {
+			exp.AddToken(p.advance())
+		}
+
+		if len(exp.Arguments) == 0 {
+			return nil // the errors have already been reported, so there's no need to validate the expression
+		}
+
+		return exp
+	}
+
 	exp.AddToken(p.expect(TokenTypeCloseParen))
 	return exp
 }
@@ -129,11 +217,31 @@ func (p *Parser) parseArgument(expressionType ExpressionType) *ArgumentNode {
 		arg.AddToken(p.advance())
 	}
 
+	// the from argument of an every expression is a date, and the from argument of a businessdays expression is a day
+	// of the month
+	valueType := expressionType
+	if (expressionType == ExpressionTypeEvery || expressionType == ExpressionTypeBusinessDays) && p.isNext(TokenTypeFrom) {
+		arg.IsFrom = true
+		arg.AddToken(p.advance())
+		if expressionType == ExpressionTypeEvery {
+			valueType = ExpressionTypeDates
+		}
+	}
+
 	if p.isNext(TokenTypeWildcard) {
 		arg.IsWildcard = true
 		arg.AddToken(p.advance())
 	} else {
-		arg.Range = p.parseRange(expressionType)
+		arg.Range = p.parseRange(valueType)
+	}
+
+	if p.isNext(TokenTypeNth) {
+		if expressionType != ExpressionTypeDaysOfWeek {
+			panic(newParseError("The # operator is only allowed in daysOfWeek expressions.", p.Input(), p.peek().Index))
+		}
+
+		arg.AddToken(p.advance())
+		arg.Nth = p.parseIntegerValue(ExpressionTypeNthValue)
 	}
 
 	if p.isNext(TokenTypeInterval) {
@@ -148,6 +256,10 @@ func (p *Parser) parseRange(expressionType ExpressionType) *RangeNode {
 	rangeNode := &RangeNode{}
 	if expressionType == ExpressionTypeDates {
 		rangeNode.Start = p.parseDate()
+	} else if expressionType == ExpressionTypeJitter || expressionType == ExpressionTypeEvery {
+		rangeNode.Start = p.parseDuration()
+	} else if expressionType == ExpressionTypeCalendar {
+		rangeNode.Start = p.parseCalendarName()
 	} else {
 		rangeNode.Start = p.parseIntegerValue(expressionType)
 	}
@@ -164,6 +276,8 @@ func (p *Parser) parseRange(expressionType ExpressionType) *RangeNode {
 		rangeNode.AddToken(p.advance())
 		if expressionType == ExpressionTypeDates {
 			rangeNode.End = p.parseDate()
+		} else if expressionType == ExpressionTypeJitter || expressionType == ExpressionTypeEvery {
+			rangeNode.End = p.parseDuration()
 		} else {
 			rangeNode.End = p.parseIntegerValue(expressionType)
 		}
@@ -181,8 +295,9 @@ func (p *Parser) parseIntegerValue(expressionType ExpressionType) *IntegerValueN
 		val.AddToken(tok)
 		val.Value = p.parseInt(tok)
 	} else if p.isNext(TokenTypeNegativeInteger) {
-		if expressionType != ExpressionTypeDaysOfMonth && expressionType != ExpressionTypeDaysOfYear {
-			panic(newParseError("Negative values are only allowed in dayofmonth and dayofyear expressions.", p.Input(), p.peek().Index))
+		if expressionType != ExpressionTypeDaysOfMonth && expressionType != ExpressionTypeDaysOfYear && expressionType != ExpressionTypeWeeks &&
+			expressionType != ExpressionTypeBusinessDays && expressionType != ExpressionTypeNthValue {
+			panic(newParseError("Negative values are only allowed in dayofmonth, dayofyear, weeks and businessdays expressions, and after the # operator.", p.Input(), p.peek().Index))
 		}
 
 		tok := p.advance()
@@ -196,20 +311,58 @@ func (p *Parser) parseIntegerValue(expressionType ExpressionType) *IntegerValueN
 		tok := p.advance()
 		val.AddToken(tok)
 		val.Value = dayToInteger(tok.Value)
+	} else if p.isNext(TokenTypeMonthLiteral) {
+		if expressionType != ExpressionTypeMonths {
+			panic(newParseError("Unexpected month literal. Month literals are only allowed in months expressions.", p.Input(), p.peek().Index))
+		}
+
+		tok := p.advance()
+		val.AddToken(tok)
+		val.Value = monthToInteger(tok.Value)
 	} else {
 		switch expressionType {
-		case ExpressionTypeDaysOfMonth, ExpressionTypeDaysOfYear:
+		case ExpressionTypeDaysOfMonth, ExpressionTypeDaysOfYear, ExpressionTypeNthValue:
 			panic(p.wrongToken(TokenTypePositiveInteger, TokenTypeNegativeInteger))
 		case ExpressionTypeDaysOfWeek:
 			panic(p.wrongToken(TokenTypePositiveInteger, TokenTypeDayLiteral))
+		case ExpressionTypeMonths:
+			panic(p.wrongToken(TokenTypePositiveInteger, TokenTypeMonthLiteral))
 		default:
 			panic(p.wrongToken(TokenTypePositiveInteger))
 		}
 	}
 
+	if p.isNext(TokenTypeDurationUnit) {
+		panic(newParseError("Unexpected duration unit. Durations are only allowed in jitter and every expressions.", p.Input(), p.peek().Index))
+	}
+
 	return val
 }
 
+func (p *Parser) parseCalendarName() *CalendarValueNode {
+	name := &CalendarValueNode{}
+
+	tok := p.expect(TokenTypeCalendarName)
+	name.AddToken(tok)
+	name.Name = strings.ToLower(tok.Value)
+
+	return name
+}
+
+func (p *Parser) parseDuration() *DurationValueNode {
+	duration := &DurationValueNode{}
+
+	tok := p.expect(TokenTypePositiveInteger)
+	duration.AddToken(tok)
+	duration.Value = p.parseInt(tok)
+
+	tok = p.expect(TokenTypeDurationUnit)
+	duration.AddToken(tok)
+	duration.Unit = tok.Value
+
+	return duration
+}
+
 func (p *Parser) parseDate() *DateValueNode {
 	date := &DateValueNode{}
 
@@ -268,6 +421,37 @@ func dayToInteger(day string) int {
 	}
 }
 
+func monthToInteger(month string) int {
+	switch month {
+	case "JANUARY":
+		return 1
+	case "FEBRUARY":
+		return 2
+	case "MARCH":
+		return 3
+	case "APRIL":
+		return 4
+	case "MAY":
+		return 5
+	case "JUNE":
+		return 6
+	case "JULY":
+		return 7
+	case "AUGUST":
+		return 8
+	case "SEPTEMBER":
+		return 9
+	case "OCTOBER":
+		return 10
+	case "NOVEMBER":
+		return 11
+	case "DECEMBER":
+		return 12
+	default:
+		panic(month + " is not a month.")
+	}
+}
+
 func (p *Parser) parseInt(tok *Token) int {
 	i, err := strconv.Atoi(tok.Value)
 	if err != nil {
@@ -16,6 +16,8 @@ var TermsCloseCurly *Terminal = &Terminal{TokenTypeCloseCurly, "}", nil, 0}
 var TermsForwardSlash *Terminal = &Terminal{TokenTypeForwardSlash, "/", nil, 0}
 var TermsComma *Terminal = &Terminal{TokenTypeComma, ",", nil, 0}
 var TermsWildcard *Terminal = &Terminal{TokenTypeWildcard, "*", nil, 0}
+var TermsNth *Terminal = &Terminal{TokenTypeNth, "#", nil, 0}
+var TermsFrom *Terminal = &Terminal{TokenTypeFrom, "", regexp.MustCompile(`(?i)^from(?:\b)`), 0}
 
 // regex terminals
 var TermsPositiveInteger *Terminal = &Terminal{TokenTypePositiveInteger, "", regexp.MustCompile(`^[0-9]+`), 0}
@@ -29,12 +31,42 @@ var TermsThursday *Terminal = &Terminal{TokenTypeDayLiteral, "THURSDAY", regexp.
 var TermsFriday *Terminal = &Terminal{TokenTypeDayLiteral, "FRIDAY", regexp.MustCompile(`(?i)^(fr|fri|friday)(?:\b)`), 0}
 var TermsSaturday *Terminal = &Terminal{TokenTypeDayLiteral, "SATURDAY", regexp.MustCompile(`(?i)^(sa|sat|saturday)(?:\b)`), 0}
 
+var TermsJanuary *Terminal = &Terminal{TokenTypeMonthLiteral, "JANUARY", regexp.MustCompile(`(?i)^(jan|january)(?:\b)`), 0}
+var TermsFebruary *Terminal = &Terminal{TokenTypeMonthLiteral, "FEBRUARY", regexp.MustCompile(`(?i)^(feb|february)(?:\b)`), 0}
+var TermsMarch *Terminal = &Terminal{TokenTypeMonthLiteral, "MARCH", regexp.MustCompile(`(?i)^(mar|march)(?:\b)`), 0}
+var TermsApril *Terminal = &Terminal{TokenTypeMonthLiteral, "APRIL", regexp.MustCompile(`(?i)^(apr|april)(?:\b)`), 0}
+var TermsMay *Terminal = &Terminal{TokenTypeMonthLiteral, "MAY", regexp.MustCompile(`(?i)^(may)(?:\b)`), 0}
+var TermsJune *Terminal = &Terminal{TokenTypeMonthLiteral, "JUNE", regexp.MustCompile(`(?i)^(jun|june)(?:\b)`), 0}
+var TermsJuly *Terminal = &Terminal{TokenTypeMonthLiteral, "JULY", regexp.MustCompile(`(?i)^(jul|july)(?:\b)`), 0}
+var TermsAugust *Terminal = &Terminal{TokenTypeMonthLiteral, "AUGUST", regexp.MustCompile(`(?i)^(aug|august)(?:\b)`), 0}
+var TermsSeptember *Terminal = &Terminal{TokenTypeMonthLiteral, "SEPTEMBER", regexp.MustCompile(`(?i)^(sep|sept|september)(?:\b)`), 0}
+var TermsOctober *Terminal = &Terminal{TokenTypeMonthLiteral, "OCTOBER", regexp.MustCompile(`(?i)^(oct|october)(?:\b)`), 0}
+var TermsNovember *Terminal = &Terminal{TokenTypeMonthLiteral, "NOVEMBER", regexp.MustCompile(`(?i)^(nov|november)(?:\b)`), 0}
+var TermsDecember *Terminal = &Terminal{TokenTypeMonthLiteral, "DECEMBER", regexp.MustCompile(`(?i)^(dec|december)(?:\b)`), 0}
+
+var TermsCalendarName *Terminal = &Terminal{TokenTypeCalendarName, "", regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*`), 0}
+
+var TermsDurationMilliseconds *Terminal = &Terminal{TokenTypeDurationUnit, "MS", regexp.MustCompile(`(?i)^ms(?:\b)`), 0}
+var TermsDurationSeconds *Terminal = &Terminal{TokenTypeDurationUnit, "S", regexp.MustCompile(`(?i)^(s|sec)(?:\b)`), 0}
+var TermsDurationMinutes *Terminal = &Terminal{TokenTypeDurationUnit, "MIN", regexp.MustCompile(`(?i)^(m|min)(?:\b)`), 0}
+var TermsDurationHours *Terminal = &Terminal{TokenTypeDurationUnit, "H", regexp.MustCompile(`(?i)^(h|hr)(?:\b)`), 0}
+var TermsDurationDays *Terminal = &Terminal{TokenTypeDurationUnit, "D", regexp.MustCompile(`(?i)^(d|day|days)(?:\b)`), 0}
+var TermsDurationWeeks *Terminal = &Terminal{TokenTypeDurationUnit, "W", regexp.MustCompile(`(?i)^(w|wk|week|weeks)(?:\b)`), 0}
+
+var TermsMilliseconds *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(ms|millisecond|milliseconds|millisecondofsecond|millisecondsofsecond)(?:\b)`), ExpressionTypeMilliseconds}
+var TermsJitter *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(jitter|splay)(?:\b)`), ExpressionTypeJitter}
+var TermsCalendar *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(cal|calendar|calendars)(?:\b)`), ExpressionTypeCalendar}
 var TermsSeconds *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(s|sec|second|seconds|secondofminute|secondsofminute)(?:\b)`), ExpressionTypeSeconds}
 var TermsMinutes *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(m|min|minute|minutes|minuteofhour|minutesofhour)(?:\b)`), ExpressionTypeMinutes}
 var TermsHours *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(h|hour|hours|hourofday|hoursofday)(?:\b)`), ExpressionTypeHours}
 var TermsDaysOfWeek *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(day|days|dow|dayofweek|daysofweek)(?:\b)`), ExpressionTypeDaysOfWeek}
 var TermsDaysOfMonth *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(dom|dayofmonth|daysofmonth)(?:\b)`), ExpressionTypeDaysOfMonth}
 var TermsDaysOfYear *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(doy|dayofyear|daysofyear)(?:\b)`), ExpressionTypeDaysOfYear}
+var TermsMonths *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(mon|month|months|monthofyear|monthsofyear)(?:\b)`), ExpressionTypeMonths}
+var TermsWeeks *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(week|weeks|weekofyear|weeksofyear)(?:\b)`), ExpressionTypeWeeks}
+var TermsEvery *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(every)(?:\b)`), ExpressionTypeEvery}
+var TermsBusinessDays *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(bday|bdays|businessday|businessdays)(?:\b)`), ExpressionTypeBusinessDays}
+var TermsYears *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(year|years)(?:\b)`), ExpressionTypeYears}
 var TermsDates *Terminal = &Terminal{TokenTypeExpressionName, "", regexp.MustCompile(`(?i)^(date|dates)(?:\b)`), ExpressionTypeDates}
 
 type Terminal struct {
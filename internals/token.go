@@ -17,6 +17,7 @@ const (
 	// meta
 	TokenTypeNone TokenType = iota
 	TokenTypeEndOfInput
+	TokenTypeError // input skipped by the lexer while recovering from an error
 
 	// operators
 	TokenTypeRangeInclusive
@@ -30,12 +31,17 @@ const (
 	TokenTypeForwardSlash
 	TokenTypeComma
 	TokenTypeWildcard
+	TokenTypeNth
 
 	// alpha-numeric
 	TokenTypePositiveInteger
 	TokenTypeNegativeInteger
 	TokenTypeExpressionName
 	TokenTypeDayLiteral
+	TokenTypeMonthLiteral
+	TokenTypeDurationUnit
+	TokenTypeCalendarName
+	TokenTypeFrom
 )
 
 var s_tokenTypeLen int = len("TokenType")
@@ -8,9 +8,9 @@ import (
 	"fmt"
 )
 
-const _TokenType_name = "TokenTypeNoneTokenTypeEndOfInputTokenTypeRangeInclusiveTokenTypeRangeHalfOpenTokenTypeIntervalTokenTypeNotTokenTypeOpenParenTokenTypeCloseParenTokenTypeOpenCurlyTokenTypeCloseCurlyTokenTypeForwardSlashTokenTypeCommaTokenTypeWildcardTokenTypePositiveIntegerTokenTypeNegativeIntegerTokenTypeExpressionNameTokenTypeDayLiteral"
+const _TokenType_name = "TokenTypeNoneTokenTypeEndOfInputTokenTypeErrorTokenTypeRangeInclusiveTokenTypeRangeHalfOpenTokenTypeIntervalTokenTypeNotTokenTypeOpenParenTokenTypeCloseParenTokenTypeOpenCurlyTokenTypeCloseCurlyTokenTypeForwardSlashTokenTypeCommaTokenTypeWildcardTokenTypeNthTokenTypePositiveIntegerTokenTypeNegativeIntegerTokenTypeExpressionNameTokenTypeDayLiteralTokenTypeMonthLiteralTokenTypeDurationUnitTokenTypeCalendarNameTokenTypeFrom"
 
-var _TokenType_index = [...]uint16{0, 13, 32, 55, 77, 94, 106, 124, 143, 161, 180, 201, 215, 232, 256, 280, 303, 322}
+var _TokenType_index = [...]uint16{0, 13, 32, 46, 69, 91, 108, 120, 138, 157, 175, 194, 215, 229, 246, 258, 282, 306, 329, 348, 369, 390, 411, 424}
 
 func (i TokenType) String() string {
 	if i < 0 || i+1 >= TokenType(len(_TokenType_index)) {
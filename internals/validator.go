@@ -9,26 +9,43 @@ import (
 type Validator struct {
 	Input   string
 	Program *ProgramNode
+	Errors  *ParseErrorList // if not nil, every error is added to the list instead of stopping at the first one
+	// IsCalendar reports whether a calendar has been registered with the name. If nil, calendar names aren't checked.
+	IsCalendar func(name string) bool
 }
 
 func (v *Validator) AssertValid() {
 	v.assertProgram(v.Program)
 }
 
-func (v *Validator) assertProgram(program *ProgramNode) {
-	if len(program.Expressions) == 0 {
-		// no free-floating expressions, so we need to make sure there is at least one group with an expression
-		hasExpressions := false
-		for _, group := range program.Groups {
-			if len(group.Expressions) > 0 {
-				hasExpressions = true
-				break
+// try calls validate. If the validator is collecting errors, a ParseError from validate is added to the list, and
+// validation continues.
+func (v *Validator) try(validate func()) {
+	if v.Errors == nil {
+		validate()
+		return
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			err, ok := e.(*ParseError)
+			if !ok {
+				panic(e)
 			}
-		}
 
-		if !hasExpressions {
-			panic(newParseError("Schedule must contain at least one expression.", v.Input, 0))
+			v.Errors.Add(err)
 		}
+	}()
+
+	validate()
+}
+
+func (v *Validator) assertProgram(program *ProgramNode) {
+	// when collecting errors, a program may be empty because its expressions had errors which were already reported
+	if len(program.Expressions) == 0 && (v.Errors == nil || v.Errors.Len() == 0) {
+		v.try(func() {
+			v.assertGroupsHaveExpressions(program)
+		})
 	}
 
 	for _, group := range program.Groups {
@@ -38,13 +55,35 @@ func (v *Validator) assertProgram(program *ProgramNode) {
 	v.assertExpressionList(program.Expressions)
 }
 
+func (v *Validator) assertGroupsHaveExpressions(program *ProgramNode) {
+	// no free-floating expressions, so we need to make sure there is at least one group with an expression
+	for _, group := range program.Groups {
+		if len(group.Expressions) > 0 {
+			return
+		}
+	}
+
+	panic(newParseError("Schedule must contain at least one expression.", v.Input, 0))
+}
+
 func (v *Validator) assertGroup(group *GroupNode) {
 	v.assertExpressionList(group.Expressions)
 }
 
 func (v *Validator) assertExpressionList(expressions []*ExpressionNode) {
+	hasJitter := false
 	for _, expression := range expressions {
-		v.assertExpression(expression)
+		v.try(func() {
+			if expression.ExpressionType == ExpressionTypeJitter {
+				if hasJitter {
+					panic(newParseError("A group can only have one jitter expression.", v.Input, expression.NameToken.Index))
+				}
+
+				hasJitter = true
+			}
+
+			v.assertExpression(expression)
+		})
 	}
 }
 
@@ -53,34 +92,119 @@ func (v *Validator) assertExpression(expression *ExpressionNode) {
 		panic(newParseError("Expression has no arguments.", v.Input, expression.Index()))
 	}
 
+	if expression.ExpressionType == ExpressionTypeJitter && len(expression.Arguments) > 1 {
+		panic(newParseError("A jitter expression can only have one argument.", v.Input, expression.Arguments[1].Index()))
+	}
+
+	if expression.ExpressionType == ExpressionTypeEvery {
+		v.assertEveryArguments(expression)
+	}
+
+	if expression.ExpressionType == ExpressionTypeBusinessDays {
+		v.assertBusinessDaysArguments(expression)
+	}
+
 	for _, arg := range expression.Arguments {
-		if arg.HasInterval() && arg.IntervalValue() == 0 {
-			panic(newParseError(`"%0" is not a valid interval. If your intention was to include all `+
-				expressionTypeToHumanString(expression.ExpressionType)+` use the wildcard operator "*" instead of an interval`, v.Input, arg.IntervalTokenIndex()))
+		v.try(func() {
+			v.assertArgument(expression, arg)
+		})
+	}
+}
+
+// assertEveryArguments checks that an every expression has a period followed by a from date.
+func (v *Validator) assertEveryArguments(expression *ExpressionNode) {
+	args := expression.Arguments
+	if len(args) != 2 || args[0].IsFrom || !args[1].IsFrom {
+		index := expression.NameToken.Index
+		if len(args) > 2 {
+			index = args[2].Index()
 		}
 
-		validator := v.getValidator(expression.ExpressionType)
+		panic(newParseError("An every expression must have a period followed by a from date, such as every(10d, from 2025/3/1).", v.Input, index))
+	}
+}
 
-		if arg.IsWildcard {
-			if arg.IsExclusion && !arg.HasInterval() {
-				panic(newParseError("Wildcards can't be excluded with the ! operator, except when part of an interval (using %).", v.Input, arg.Index()))
-			}
-		} else {
-			if arg.Range == nil || arg.Range.Start == nil {
-				panic(newParseError("Expected a value or range.", v.Input, arg.Index()))
+// assertBusinessDaysArguments checks that a businessdays expression has at most one from argument, which comes after
+// every business day. Business days which count from a day of the month either count forward or back, so a range can't
+// mix positive and negative values.
+func (v *Validator) assertBusinessDaysArguments(expression *ExpressionNode) {
+	args := expression.Arguments
+	last := len(args) - 1
+	for i, arg := range args {
+		if arg.IsFrom && (i != last || i == 0) {
+			panic(newParseError("The from argument of a businessdays expression must follow the business days, such as bday(-1, from 15).", v.Input, arg.Index()))
+		}
+	}
+
+	if !args[last].IsFrom {
+		return
+	}
+
+	for _, arg := range args[:last] {
+		if arg.IsRange() {
+			start := arg.Range.Start.(*IntegerValueNode).Value
+			end := arg.Range.End.(*IntegerValueNode).Value
+			if (start < 0) != (end < 0) {
+				panic(newParseError("Business days which count from a day of the month can't mix positive and negative values in a range.", v.Input, arg.Index()))
 			}
+		}
+	}
+}
 
-			v.assertRange(expression.ExpressionType, arg.Range, validator)
+func (v *Validator) assertArgument(expression *ExpressionNode, arg *ArgumentNode) {
+	if expression.ExpressionType == ExpressionTypeJitter && (arg.IsExclusion || arg.IsWildcard || arg.IsRange() || arg.HasInterval()) {
+		panic(newParseError("The argument of a jitter expression must be a single duration, such as 30s.", v.Input, arg.Index()))
+	}
+
+	if expression.ExpressionType == ExpressionTypeEvery && (arg.IsExclusion || arg.IsWildcard || arg.IsRange() || arg.HasInterval()) {
+		panic(newParseError("The arguments of an every expression must be a single period and a single date, such as every(2w, from 2025/1/6).", v.Input, arg.Index()))
+	}
+
+	if expression.ExpressionType == ExpressionTypeBusinessDays && arg.IsFrom {
+		if arg.IsExclusion || arg.IsWildcard || arg.IsRange() || arg.HasInterval() {
+			panic(newParseError("The from argument of a businessdays expression must be a single day of the month, such as from 15.", v.Input, arg.Index()))
 		}
 
-		if arg.HasInterval() {
-			validator(ExpressionTypeIntervalValue, arg.Interval)
+		v.integerValue(ExpressionTypeDaysOfMonth, arg.Range.Start, 1, 31)
+		return
+	}
+
+	if arg.HasInterval() && arg.IntervalValue() == 0 {
+		panic(newParseError(`"%0" is not a valid interval. If your intention was to include all `+
+			expressionTypeToHumanString(expression.ExpressionType)+` use the wildcard operator "*" instead of an interval`, v.Input, arg.IntervalTokenIndex()))
+	}
+
+	validator := v.getValidator(expression.ExpressionType)
+
+	if arg.IsWildcard {
+		if arg.IsExclusion && !arg.HasInterval() {
+			panic(newParseError("Wildcards can't be excluded with the ! operator, except when part of an interval (using %).", v.Input, arg.Index()))
 		}
+	} else {
+		if arg.Range == nil || arg.Range.Start == nil {
+			panic(newParseError("Expected a value or range.", v.Input, arg.Index()))
+		}
+
+		v.assertRange(expression.ExpressionType, arg.Range, validator)
+	}
+
+	if arg.HasInterval() {
+		validator(ExpressionTypeIntervalValue, arg.Interval)
+	}
+
+	if arg.HasNth() {
+		if arg.IsWildcard || arg.IsRange() || arg.HasInterval() {
+			panic(newParseError("The # operator can only be applied to a single day of the week, not to a range, wildcard or interval.", v.Input, arg.NthTokenIndex()))
+		}
+
+		v.nth(ExpressionTypeNthValue, arg.Nth)
 	}
 }
 
 func (v *Validator) getValidator(expType ExpressionType) ValueValidator {
 	switch expType {
+	case ExpressionTypeMilliseconds:
+		return v.millisecond
 	case ExpressionTypeSeconds, ExpressionTypeMinutes:
 		return v.secondOrMinute
 	case ExpressionTypeHours:
@@ -91,8 +215,22 @@ func (v *Validator) getValidator(expType ExpressionType) ValueValidator {
 		return v.dayOfMonth
 	case ExpressionTypeDaysOfYear:
 		return v.dayOfYear
+	case ExpressionTypeMonths:
+		return v.month
 	case ExpressionTypeDates:
 		return v.date
+	case ExpressionTypeWeeks:
+		return v.week
+	case ExpressionTypeYears:
+		return v.year
+	case ExpressionTypeBusinessDays:
+		return v.businessDay
+	case ExpressionTypeEvery:
+		return v.every
+	case ExpressionTypeJitter:
+		return v.jitter
+	case ExpressionTypeCalendar:
+		return v.calendar
 	default:
 		panic("ExpressionType " + expType.Name() + " has not been implemented by the validator.")
 	}
@@ -110,6 +248,12 @@ func (v *Validator) assertRange(expType ExpressionType, rangeNode *RangeNode, va
 		}
 	}
 
+	if expType == ExpressionTypeYears && rangeNode.End != nil {
+		if rangeNode.End.(*IntegerValueNode).Value < rangeNode.Start.(*IntegerValueNode).Value {
+			panic(newParseError("End year of range is before the start year.", v.Input, rangeNode.Start.Index()))
+		}
+	}
+
 	if expType == ExpressionTypeDates && rangeNode.End != nil {
 		// special validation to make the date range is sane
 		start := rangeNode.Start.(*DateValueNode)
@@ -127,6 +271,10 @@ func (v *Validator) assertRange(expType ExpressionType, rangeNode *RangeNode, va
 	}
 }
 
+func (v *Validator) millisecond(expType ExpressionType, value ValueNode) {
+	v.integerValue(expType, value, 0, 999)
+}
+
 func (v *Validator) secondOrMinute(expType ExpressionType, value ValueNode) {
 	v.integerValue(expType, value, 0, 59)
 }
@@ -139,6 +287,13 @@ func (v *Validator) dayOfWeek(expType ExpressionType, value ValueNode) {
 	v.integerValue(expType, value, 1, 7)
 }
 
+func (v *Validator) nth(expType ExpressionType, value ValueNode) {
+	ival := v.integerValue(expType, value, -5, 5)
+	if ival == 0 {
+		panic(newParseError("Occurrence of a day within the month cannot be zero.", v.Input, value.Index()))
+	}
+}
+
 func (v *Validator) dayOfMonth(expType ExpressionType, value ValueNode) {
 	ival := v.integerValue(expType, value, -31, 31)
 	if ival == 0 {
@@ -153,7 +308,31 @@ func (v *Validator) dayOfYear(expType ExpressionType, value ValueNode) {
 	}
 }
 
+func (v *Validator) week(expType ExpressionType, value ValueNode) {
+	ival := v.integerValue(expType, value, -53, 53)
+	if ival == 0 {
+		panic(newParseError("Week of year cannot be zero.", v.Input, value.Index()))
+	}
+}
+
+func (v *Validator) businessDay(expType ExpressionType, value ValueNode) {
+	ival := v.integerValue(expType, value, -31, 31)
+	if ival == 0 {
+		panic(newParseError("Business day cannot be zero.", v.Input, value.Index()))
+	}
+}
+
+func (v *Validator) month(expType ExpressionType, value ValueNode) {
+	v.integerValue(expType, value, 1, 12)
+}
+
 func (v *Validator) date(expType ExpressionType, value ValueNode) {
+	if expType == ExpressionTypeIntervalValue {
+		// the interval of a date range is a number of days
+		v.integerValue(expType, value, 0, 366)
+		return
+	}
+
 	date := value.(*DateValueNode)
 
 	if date.HasYear {
@@ -178,6 +357,52 @@ func (v *Validator) date(expType ExpressionType, value ValueNode) {
 	}
 }
 
+func (v *Validator) year(expType ExpressionType, value ValueNode) {
+	if expType == ExpressionTypeIntervalValue {
+		v.integerValue(expType, value, 0, 300)
+		return
+	}
+
+	year := value.(*IntegerValueNode)
+	if year.Value < 1900 || year.Value > 2200 {
+		panic(newParseError("Year "+strconv.Itoa(year.Value)+" is not a valid year. Must be between 1900 and 2200.", v.Input, year.Index()))
+	}
+}
+
+func (v *Validator) every(expType ExpressionType, value ValueNode) {
+	if date, ok := value.(*DateValueNode); ok {
+		if !date.HasYear {
+			panic(newParseError("The from date of an every expression must include a year.", v.Input, date.Index()))
+		}
+
+		v.date(ExpressionTypeDates, date)
+		return
+	}
+
+	period := value.(*DurationValueNode)
+	if period.Unit != "D" && period.Unit != "W" {
+		panic(newParseError("The period of an every expression must be a number of days or weeks, such as 10d or 2w.", v.Input, period.Index()))
+	}
+
+	if days := period.Milliseconds() / (24 * 60 * 60 * 1000); days < 1 || days > 366 {
+		panic(newParseError("Period cannot be "+strconv.Itoa(period.Value)+strings.ToLower(period.Unit)+". Value must be between 1d and 366d.", v.Input, period.Index()))
+	}
+}
+
+func (v *Validator) jitter(expType ExpressionType, value ValueNode) {
+	duration := value.(*DurationValueNode)
+	if ms := duration.Milliseconds(); ms < 1 || ms > 24*60*60*1000 {
+		panic(newParseError("Jitter cannot be "+strconv.Itoa(duration.Value)+strings.ToLower(duration.Unit)+". Value must be between 1ms and 24h.", v.Input, duration.Index()))
+	}
+}
+
+func (v *Validator) calendar(expType ExpressionType, value ValueNode) {
+	name := value.(*CalendarValueNode)
+	if v.IsCalendar != nil && !v.IsCalendar(name.Name) {
+		panic(newParseError(`Unknown calendar "`+name.Name+`".`, v.Input, name.Index()))
+	}
+}
+
 func (v *Validator) integerValue(expType ExpressionType, value ValueNode, min, max int) int {
 	ival := value.(*IntegerValueNode).Value
 	if ival < min || ival > max {
@@ -244,8 +469,14 @@ func expressionTypeToHumanString(expType ExpressionType) string {
 		return "days of the month"
 	case ExpressionTypeDaysOfWeek:
 		return "days of the week"
+	case ExpressionTypeWeeks:
+		return "weeks of the year"
+	case ExpressionTypeBusinessDays:
+		return "business days"
 	case ExpressionTypeIntervalValue:
 		return "interval"
+	case ExpressionTypeNthValue:
+		return "occurrence of a day within the month"
 	default:
 		return strings.ToLower(expType.Name())
 	}
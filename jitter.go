@@ -0,0 +1,37 @@
+package schyntax
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+func (s *scheduleImpl) ForKey(key string) Schedule {
+	keyed := *s
+	keyed.key = key
+	return &keyed
+}
+
+// jitterOffset returns the offset which is added to every event of the group. It only depends on the schedule's key,
+// so every event moves by the same amount, which keeps searches in either direction consistent with each other.
+func (s *scheduleImpl) jitterOffset(group *internals.IrGroup) time.Duration {
+	if group.Jitter == 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(s.key))
+	return time.Duration(h.Sum64()%uint64(group.Jitter)) * time.Millisecond
+}
+
+// hasJitter returns true if any group in the schedule has a jitter expression.
+func (s *scheduleImpl) hasJitter() bool {
+	for _, group := range s.ir.Groups {
+		if group.Jitter != 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+package schyntax
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	sch, err := New("min(*%5) jitter(30s)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, _ := New("min(*%5)")
+	date := parseTestTime(t, "2025-06-15T10:02:00Z")
+	base, _ := plain.NextAfter(date.Add(-30 * time.Second))
+
+	offsets := make(map[time.Duration]bool)
+	for _, key := range []string{"", "host-1", "host-2", "host-3", "nightly-backup"} {
+		keyed := sch.ForKey(key)
+		next, err := keyed.NextAfter(date)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		offset := next.Sub(base)
+		if offset < 0 || offset >= 30*time.Second || offset%time.Millisecond != 0 {
+			t.Errorf("%q: offset %s is not a whole millisecond less than 30s", key, offset)
+		}
+
+		offsets[offset] = true
+
+		if again, _ := sch.ForKey(key).NextAfter(date); !again.Equal(next) {
+			t.Errorf("%q: expected the same event for the same key, got %s and %s", key, next, again)
+		}
+
+		// Next and Previous agree with each other
+		if prev, _ := keyed.PreviousAtOrBefore(next); !prev.Equal(next) {
+			t.Errorf("%q: PreviousAtOrBefore(%s) returned %s", key, next, prev)
+		}
+
+		prev, _ := keyed.PreviousBefore(next)
+		if !prev.Equal(next.Add(-5 * time.Minute)) {
+			t.Errorf("%q: expected the previous event 5 minutes before %s, got %s", key, next, prev)
+		}
+
+		if after, _ := keyed.NextAfter(prev); !after.Equal(next) {
+			t.Errorf("%q: NextAfter(%s) returned %s, expected %s", key, prev, after, next)
+		}
+
+		events := keyed.ListOccurrences(prev, next.Add(time.Nanosecond), 0)
+		if len(events) != 2 || !events[0].Equal(prev) || !events[1].Equal(next) {
+			t.Errorf("%q: unexpected occurrences %v", key, events)
+		}
+	}
+
+	if len(offsets) < 2 {
+		t.Errorf("Expected different keys to have different offsets")
+	}
+
+	// groups without jitter aren't affected by the key
+	sch, _ = New("{h(9) jitter(1h)}, {h(12)}")
+	if next, _ := sch.ForKey("host-1").NextAfter(parseTestTime(t, "2025-06-15T11:00:00Z")); !next.Equal(parseTestTime(t, "2025-06-15T12:00:00Z")) {
+		t.Errorf("Expected noon, got %s", next)
+	}
+}
+
+func TestJitterErrors(t *testing.T) {
+	checks := []*check{
+		newParseErrorCheck("jitter(30)", 9),
+		newParseErrorCheck("s(30s)", 4),
+		newParseErrorCheck("jitter(0s)", 7),
+		newParseErrorCheck("jitter(25h)", 7),
+		newParseErrorCheck("jitter(10s, 20s)", 12),
+		newParseErrorCheck("jitter(*)", 7),
+		newParseErrorCheck("jitter(!5s)", 7),
+		newParseErrorCheck("jitter(1s..5s)", 7),
+		newParseErrorCheck("{h(9) jitter(1s) jitter(2s)}", 17),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+}
+
+func TestJitterText(t *testing.T) {
+	formatted, err := Format("JITTER(90 sec) min(*%5)", SortExpressions())
+	if err != nil || formatted != "min(*%5) jitter(90s)" {
+		t.Errorf("Unexpected format %q (%v)", formatted, err)
+	}
+
+	for d, expected := range map[time.Duration]string{
+		90 * time.Second:        "jitter(90s)",
+		2 * time.Minute:         "jitter(2min)",
+		time.Hour:               "jitter(1h)",
+		1500 * time.Millisecond: "jitter(1500ms)",
+	} {
+		if text := Jitter(d).String(); text != expected {
+			t.Errorf("Jitter(%s): expected %q, got %q", d, expected, text)
+		}
+	}
+
+	description, _ := Describe("h(9) jitter(15min)")
+	if description != "at 09:00, delayed by less than 15 minutes" {
+		t.Errorf("Unexpected description %q", description)
+	}
+}
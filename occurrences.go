@@ -0,0 +1,161 @@
+package schyntax
+
+import (
+	"container/heap"
+	"iter"
+	"time"
+)
+
+func (s *scheduleImpl) Occurrences(from, to time.Time) iter.Seq[time.Time] {
+	return s.events(from.Add(-time.Nanosecond), searchModeAfter, func(e time.Time) bool {
+		return e.Before(to)
+	})
+}
+
+func (s *scheduleImpl) OccurrencesBackward(from, to time.Time) iter.Seq[time.Time] {
+	return s.events(to.Add(-time.Nanosecond), searchModeAtOrBefore, func(e time.Time) bool {
+		return !e.Before(from)
+	})
+}
+
+func (s *scheduleImpl) ListOccurrences(from, to time.Time, max int) []time.Time {
+	return collectEvents(s.Occurrences(from, to), max)
+}
+
+func (s *scheduleImpl) ListOccurrencesBackward(from, to time.Time, max int) []time.Time {
+	return collectEvents(s.OccurrencesBackward(from, to), max)
+}
+
+func (s *scheduleImpl) NextN(after time.Time, n int) []time.Time {
+	return s.nEvents(after, searchModeAfter, n)
+}
+
+func (s *scheduleImpl) PreviousN(atOrBefore time.Time, n int) []time.Time {
+	return s.nEvents(atOrBefore, searchModeAtOrBefore, n)
+}
+
+func (s *scheduleImpl) nEvents(start time.Time, mode searchMode, n int) []time.Time {
+	if n <= 0 {
+		return nil
+	}
+
+	return collectEvents(s.events(start, mode, func(time.Time) bool { return true }), n)
+}
+
+func collectEvents(events iter.Seq[time.Time], max int) []time.Time {
+	var list []time.Time
+	for e := range events {
+		list = append(list, e)
+		if max > 0 && len(list) >= max {
+			break
+		}
+	}
+
+	return list
+}
+
+// groupCursor is the position of one group's search, along with the event or window it's currently at.
+type groupCursor[T any] struct {
+	next  func() (T, bool)
+	stop  func()
+	event T
+}
+
+// cursorHeap orders group cursors so that the cursor with the nearest event in the search direction is first.
+type cursorHeap[T any] struct {
+	cursors []*groupCursor[T]
+	less    func(a, b T) bool
+}
+
+// newCursorHeap starts each sequence, and orders the ones which have at least one event. The heap's stop method must be
+// called once it's no longer needed.
+func newCursorHeap[T any](sequences []iter.Seq[T], less func(a, b T) bool) *cursorHeap[T] {
+	h := &cursorHeap[T]{make([]*groupCursor[T], 0, len(sequences)), less}
+	for _, seq := range sequences {
+		next, stop := iter.Pull(seq)
+		if e, ok := next(); ok {
+			h.cursors = append(h.cursors, &groupCursor[T]{next, stop, e})
+		} else {
+			stop()
+		}
+	}
+
+	heap.Init(h)
+	return h
+}
+
+// advance moves the first cursor to its next event, removing it if there isn't one.
+func (h *cursorHeap[T]) advance() {
+	c := h.cursors[0]
+	if e, ok := c.next(); ok {
+		c.event = e
+		heap.Fix(h, 0)
+	} else {
+		c.stop()
+		heap.Pop(h)
+	}
+}
+
+func (h *cursorHeap[T]) stop() {
+	for _, c := range h.cursors {
+		c.stop()
+	}
+}
+
+func (h *cursorHeap[T]) Len() int {
+	return len(h.cursors)
+}
+
+func (h *cursorHeap[T]) Less(i, j int) bool {
+	return h.less(h.cursors[i].event, h.cursors[j].event)
+}
+
+func (h *cursorHeap[T]) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+func (h *cursorHeap[T]) Push(x any) {
+	h.cursors = append(h.cursors, x.(*groupCursor[T]))
+}
+
+func (h *cursorHeap[T]) Pop() any {
+	last := h.cursors[len(h.cursors)-1]
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return last
+}
+
+// events merges the events of every group into a single iterator which moves in the direction of the search mode.
+// Each group's search is resumed where it left off rather than restarted for every event, and a heap finds the group
+// with the nearest event. Iteration ends at the first event for which inWindow returns false.
+func (s *scheduleImpl) events(start time.Time, mode searchMode, inWindow func(time.Time) bool) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		start := start.In(s.loc)
+
+		sequences := make([]iter.Seq[time.Time], len(s.ir.Groups))
+		for i, group := range s.ir.Groups {
+			sequences[i] = s.groupEvents(group, start, mode)
+		}
+
+		h := newCursorHeap(sequences, mode.isNearer)
+		defer h.stop()
+
+		var previous time.Time
+		for h.Len() > 0 {
+			e := h.cursors[0].event
+			if !inWindow(e) {
+				return
+			}
+
+			// multiple groups may have an event at the same instant, but it's only reported once
+			if previous.IsZero() || !e.Equal(previous) {
+				if !yield(e) {
+					return
+				}
+
+				previous = e
+			}
+
+			h.advance()
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package schyntax
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOccurrences(t *testing.T) {
+	sch, err := New(`{h(9) min(*%15)}, {h(9..10) min(0)}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := parseTestTime(t, "2025-06-02T09:15:00Z")
+	to := parseTestTime(t, "2025-06-02T11:00:00Z")
+	expected := []string{
+		"2025-06-02T09:15:00Z",
+		"2025-06-02T09:30:00Z",
+		"2025-06-02T09:45:00Z",
+		"2025-06-02T10:00:00Z",
+	}
+
+	assertEvents(t, "forward", sch.ListOccurrences(from, to, 0), expected)
+	assertEvents(t, "forward max", sch.ListOccurrences(from, to, 2), expected[:2])
+
+	reversed := make([]string, len(expected))
+	for i, e := range expected {
+		reversed[len(expected)-1-i] = e
+	}
+
+	assertEvents(t, "backward", sch.ListOccurrencesBackward(from, to, 0), reversed)
+	assertEvents(t, "backward max", sch.ListOccurrencesBackward(from, to, 3), reversed[:3])
+
+	// a window which ends before it starts is empty
+	if events := sch.ListOccurrences(to, from, 0); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+
+	// stopping early
+	count := 0
+	for range sch.Occurrences(from, to.AddDate(1, 0, 0)) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	if count != 10 {
+		t.Errorf("Expected to stop after 10 events, got %d", count)
+	}
+}
+
+func TestOccurrencesMatchNextAndPrevious(t *testing.T) {
+	sch, err := New(`{dow(mon..fri) h(9..<17) min(*%20)}, {dates(12/24..12/26) h(0)}, {dom(-1) h(12)}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := parseTestTime(t, "2025-12-20T00:00:00Z")
+	to := parseTestTime(t, "2026-01-10T00:00:00Z")
+
+	expected := []time.Time{}
+	for e, err := sch.NextAfter(from.Add(-time.Nanosecond)); err == nil && e.Before(to); e, err = sch.NextAfter(e) {
+		expected = append(expected, e)
+	}
+
+	events := sch.ListOccurrences(from, to, 0)
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+
+	for i := range events {
+		if !events[i].Equal(expected[i]) {
+			t.Fatalf("Event %d. Expected: %s, Actual: %s", i, expected[i], events[i])
+		}
+	}
+
+	backward := sch.ListOccurrencesBackward(from, to, 0)
+	for i := range backward {
+		if !backward[i].Equal(expected[len(expected)-1-i]) {
+			t.Fatalf("Backward event %d. Expected: %s, Actual: %s", i, expected[len(expected)-1-i], backward[i])
+		}
+	}
+}
+
+func assertEvents(t *testing.T, name string, events []time.Time, expected []string) {
+	if len(events) != len(expected) {
+		t.Errorf("%s: expected %d events, got %d: %v", name, len(expected), len(events), events)
+		return
+	}
+
+	for i, e := range events {
+		if !e.Equal(parseTestTime(t, expected[i])) {
+			t.Errorf("%s: event %d. Expected: %s, Actual: %s", name, i, expected[i], e)
+		}
+	}
+}
+
+func TestOccurrencesAcrossDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is unavailable: ", err)
+	}
+
+	sch, err := NewInLocation(`min(*%30)`, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 02:00 and 02:30 are skipped, and only fire once at the transition
+	from := parseTestTime(t, "2025-03-09T01:00:00-05:00")
+	to := parseTestTime(t, "2025-03-09T04:00:00-04:00")
+	expected := []string{
+		"2025-03-09T01:00:00-05:00",
+		"2025-03-09T01:30:00-05:00",
+		"2025-03-09T03:00:00-04:00",
+		"2025-03-09T03:30:00-04:00",
+	}
+
+	assertEvents(t, "spring forward", sch.ListOccurrences(from, to, 0), expected)
+
+	// 01:00 and 01:30 are repeated, but only fire during their first occurrence
+	from = parseTestTime(t, "2025-11-02T00:30:00-04:00")
+	to = parseTestTime(t, "2025-11-02T02:30:00-05:00")
+	expected = []string{
+		"2025-11-02T00:30:00-04:00",
+		"2025-11-02T01:00:00-04:00",
+		"2025-11-02T01:30:00-04:00",
+		"2025-11-02T02:00:00-05:00",
+	}
+
+	assertEvents(t, "fall back", sch.ListOccurrences(from, to, 0), expected)
+}
+
+func TestNextNAndPreviousN(t *testing.T) {
+	sch, err := New(`{dow(mon..fri) h(9..<17) min(*%20)}, {dates(12/24..12/26) h(0)}, {dom(-1) h(12)}, {h(9) min(0)}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := parseTestTime(t, "2025-12-23T16:00:00Z")
+
+	var expected []time.Time
+	for e, err := sch.NextAfter(start); err == nil && len(expected) < 50; e, err = sch.NextAfter(e) {
+		expected = append(expected, e)
+	}
+
+	events := sch.NextN(start, 50)
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+
+	for i := range events {
+		if !events[i].Equal(expected[i]) {
+			t.Fatalf("Event %d. Expected: %s, Actual: %s", i, expected[i], events[i])
+		}
+	}
+
+	expected = nil
+	for e, err := sch.PreviousAtOrBefore(start); err == nil && len(expected) < 50; e, err = sch.PreviousBefore(e) {
+		expected = append(expected, e)
+	}
+
+	events = sch.PreviousN(start, 50)
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d previous events, got %d", len(expected), len(events))
+	}
+
+	for i := range events {
+		if !events[i].Equal(expected[i]) {
+			t.Fatalf("Previous event %d. Expected: %s, Actual: %s", i, expected[i], events[i])
+		}
+	}
+
+	if events := sch.NextN(start, 0); events != nil {
+		t.Errorf("Expected no events, got %v", events)
+	}
+
+	// the search ends once there are no more events within the horizon
+	sch, _ = New(`dates(2025/12/24, 2025/12/25)`)
+	assertEvents(t, "horizon", sch.NextN(start, 5), []string{"2025-12-24T00:00:00Z", "2025-12-25T00:00:00Z"})
+}
@@ -0,0 +1,76 @@
+package schyntax
+
+import "time"
+
+// Option configures a Schedule when it is created by New.
+type Option func(*options)
+
+// DefaultSearchHorizon is the number of days a schedule searches for an event, unless changed by the SearchHorizon
+// option.
+const DefaultSearchHorizon = 4 * 365
+
+type options struct {
+	location  *time.Location
+	horizon   int
+	strict    bool
+	allErrors bool
+	calendars map[string]Calendar
+	weekend   uint8 // bit n is set if time.Weekday(n) isn't a business day
+	holidays  Calendar
+}
+
+func defaultOptions() options {
+	return options{
+		location: time.UTC,
+		horizon:  DefaultSearchHorizon,
+		weekend:  defaultWeekend,
+	}
+}
+
+// InLocation causes every expression in the schedule to be evaluated against wall clock time in loc rather than UTC.
+// Results are returned in loc. Wall clock times which are skipped by a daylight saving transition fire at the instant
+// of the transition, and wall clock times which are repeated only fire during their first occurrence.
+//
+// InLocation panics if loc is nil.
+func InLocation(loc *time.Location) Option {
+	if loc == nil {
+		panic("schyntax: InLocation called with a nil location.")
+	}
+
+	return func(o *options) {
+		o.location = loc
+	}
+}
+
+// SearchHorizon sets the number of days a schedule searches, in either direction, before concluding that there is no
+// matching event and returning a ValidTimeNotFoundError. When iterating over events, the horizon applies to the gap
+// between consecutive events rather than to the whole iteration.
+//
+// SearchHorizon panics if days is less than one.
+func SearchHorizon(days int) Option {
+	if days < 1 {
+		panic("schyntax: SearchHorizon must be at least one day.")
+	}
+
+	return func(o *options) {
+		o.horizon = days
+	}
+}
+
+// Strict causes New to check that every group of expressions can match at least one time, and return an
+// *UnsatisfiableError describing the contradicting expressions if not. For example, "dom(31) dates(2/1..2/28)" is
+// syntactically valid, but can never match.
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// ReportAllErrors causes New to recover from lexical, syntax and validation errors and keep going, so that every
+// problem in the schedule is found at once. If there are any errors, New returns an *internals.ParseErrorList, whose
+// Index method returns the index of the first error, and whose Errors method returns every error in order.
+func ReportAllErrors() Option {
+	return func(o *options) {
+		o.allErrors = true
+	}
+}
@@ -0,0 +1,236 @@
+package schyntax
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// The Gregorian calendar repeats every 400 years, and years must be between 1900 and 2200, so a group which doesn't
+// match any day between 1900 and 2299 can never match.
+const (
+	satisfiabilityFirstYear = 1900
+	satisfiabilityLastYear  = 2299
+)
+
+// Contradiction describes a group of expressions which can never match at the same time.
+type Contradiction struct {
+	// GroupIndex is the index of the group's opening curly brace in the schedule's text, or -1 if the expressions are
+	// not inside curly braces.
+	GroupIndex int
+	// ExpressionIndexes are the indexes of the contradicting expressions' names in the schedule's text. Removing any
+	// one of these expressions would resolve the contradiction.
+	ExpressionIndexes []int
+	// Expressions is the text of each contradicting expression.
+	Expressions []string
+}
+
+// UnsatisfiableError is returned by New when the Strict option is used and at least one group of expressions can never
+// match any time.
+type UnsatisfiableError struct {
+	input          string
+	contradictions []*Contradiction
+}
+
+func (e *UnsatisfiableError) Error() string {
+	msgs := make([]string, len(e.contradictions))
+	for i, c := range e.contradictions {
+		if len(c.Expressions) == 1 {
+			msgs[i] = "The expression " + c.Expressions[0] + " can never match."
+		} else {
+			msgs[i] = "The expressions " + strings.Join(c.Expressions, ", ") + " can never match at the same time."
+		}
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+func (e *UnsatisfiableError) Input() string {
+	return e.input
+}
+
+// Index returns the index of the first contradicting expression.
+func (e *UnsatisfiableError) Index() int {
+	return e.contradictions[0].ExpressionIndexes[0]
+}
+
+// Contradictions returns one contradiction for every group which can never match, in the order they appear in the
+// schedule's text.
+func (e *UnsatisfiableError) Contradictions() []*Contradiction {
+	return e.contradictions
+}
+
+// assertSatisfiable panics with an *UnsatisfiableError if any group in the program can never match.
+func (s *scheduleImpl) assertSatisfiable(program *internals.ProgramNode) {
+	var contradictions []*Contradiction
+
+	check := func(groupIndex int, expressions []*internals.ExpressionNode) {
+		if len(expressions) == 0 {
+			return
+		}
+
+		core := s.findContradiction(expressions)
+		if core == nil {
+			return
+		}
+
+		c := &Contradiction{GroupIndex: groupIndex}
+		for _, exp := range core {
+			c.ExpressionIndexes = append(c.ExpressionIndexes, exp.NameToken.Index)
+			c.Expressions = append(c.Expressions, s.expressionText(exp))
+		}
+
+		contradictions = append(contradictions, c)
+	}
+
+	// keep contradictions in text order, which means free-floating expressions may need to be reported after some groups
+	freeIndex := -1
+	if len(program.Expressions) > 0 {
+		freeIndex = program.Expressions[0].NameToken.Index
+	}
+
+	for _, group := range program.Groups {
+		if freeIndex != -1 && freeIndex < group.Index() {
+			check(-1, program.Expressions)
+			freeIndex = -1
+		}
+
+		check(group.Index(), group.Expressions)
+	}
+
+	if freeIndex != -1 {
+		check(-1, program.Expressions)
+	}
+
+	if len(contradictions) > 0 {
+		panic(&UnsatisfiableError{s.originalText, contradictions})
+	}
+}
+
+// findContradiction returns nil if the expressions can match at least one time. Otherwise, it returns a minimal subset
+// of the expressions which can never match at the same time.
+func (s *scheduleImpl) findContradiction(expressions []*internals.ExpressionNode) []*internals.ExpressionNode {
+	if s.isSatisfiable(internals.CompileGroup(expressions)) {
+		return nil
+	}
+
+	// Removing an expression can only make a group match more times (implied rules never conflict with anything), so
+	// drop every expression which isn't needed to keep the group unsatisfiable.
+	core := expressions
+	for i := 0; i < len(core); {
+		candidate := make([]*internals.ExpressionNode, 0, len(core)-1)
+		candidate = append(candidate, core[:i]...)
+		candidate = append(candidate, core[i+1:]...)
+
+		if len(candidate) > 0 && !s.isSatisfiable(internals.CompileGroup(candidate)) {
+			core = candidate
+		} else {
+			i++
+		}
+	}
+
+	return core
+}
+
+// isSatisfiable returns true if the group matches at least one time. The time of day only depends on the masks, and
+// days are checked with isApplicableDate, but only in the months of the group's months mask, and only in one year of
+// each kind: years which start on the same day of the week, and whose neighbours are leap years in the same way, have
+// the same days of the week, days of the year and ISO weeks on every date. That is about 30 years rather than 400.
+//
+// Groups which depend on particular years, through dates with years, every expressions, calendars or business days
+// with holidays, have every applicable year checked, which is up to 146,097 days and takes several milliseconds. Dates
+// with years limit the search to the years they span.
+func (s *scheduleImpl) isSatisfiable(group *internals.IrGroup) bool {
+	if group.HoursMask == 0 || group.MinutesMask == 0 || group.SecondsMask == 0 || group.MillisecondsMask.IsEmpty() || group.MonthsMask == 0 {
+		return false
+	}
+
+	first, last := satisfiabilityFirstYear, satisfiabilityLastYear
+	if start, end, ok := datedYears(group); ok {
+		first, last = max(first, start), min(last, end)
+	}
+
+	byKind := !group.HasPeriods() && !group.HasCalendars() && !group.HasCalendarsExcluded() && !hasDatesWithYears(group) &&
+		(s.holidays == nil || !(group.HasBusinessDays() || group.HasBusinessDaysExcluded()))
+
+	type yearKind struct {
+		weekday                  time.Weekday
+		prevLeap, leap, nextLeap bool
+	}
+
+	checked := make(map[yearKind]bool)
+	for year := first; year <= last; year++ {
+		if !group.IsApplicableYear(year) {
+			continue
+		}
+
+		if byKind {
+			kind := yearKind{
+				time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC).Weekday(),
+				internals.IsLeapYear(year - 1), internals.IsLeapYear(year), internals.IsLeapYear(year + 1),
+			}
+
+			if checked[kind] {
+				continue
+			}
+
+			checked[kind] = true
+		}
+
+		for month := 1; month <= 12; month++ {
+			if group.MonthsMask&(1<<uint(month)) == 0 {
+				continue
+			}
+
+			for day := 1; day <= internals.DaysInMonth(year, month); day++ {
+				if s.isApplicableDate(group, time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// datedYears returns the first and last years which the group's dates span, if every date it includes has a year.
+func datedYears(group *internals.IrGroup) (first, last int, ok bool) {
+	if !group.HasDates() {
+		return 0, 0, false
+	}
+
+	first, last = math.MaxInt, math.MinInt
+	for _, r := range group.Dates {
+		if !r.DatesHaveYear {
+			return 0, 0, false
+		}
+
+		first = min(first, r.Start.Year)
+		last = max(last, r.Start.Year)
+		if r.End != nil {
+			last = max(last, r.End.Year)
+		}
+	}
+
+	return first, last, true
+}
+
+// hasDatesWithYears returns true if any date the group includes or excludes has a year.
+func hasDatesWithYears(group *internals.IrGroup) bool {
+	for _, ranges := range [][]*internals.IrDateRange{group.Dates, group.DatesExcluded} {
+		for _, r := range ranges {
+			if r.DatesHaveYear {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *scheduleImpl) expressionText(exp *internals.ExpressionNode) string {
+	last := exp.Tokens[len(exp.Tokens)-1]
+	return s.originalText[exp.NameToken.Index : last.Index+len(last.RawValue)]
+}
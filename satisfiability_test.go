@@ -0,0 +1,119 @@
+package schyntax
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+func TestStrictRejectsContradictions(t *testing.T) {
+	tests := []struct {
+		format      string
+		groups      []int
+		expressions [][]int
+	}{
+		{"dom(31) dates(2/1..2/28)", []int{-1}, [][]int{{0, 8}}},
+		{"dow(mon) h(9) dates(2020/1/1)", []int{-1}, [][]int{{0, 14}}},
+		{"h(!*%1)", []int{-1}, [][]int{{0}}},
+		{"months(jan) dow(tue#-1) dom(1..7)", []int{-1}, [][]int{{12, 24}}},
+		{"{dom(31) h(5) dates(2/1..2/28)}, min(5)", []int{0}, [][]int{{1, 14}}},
+		{"min(5), {dates(4/30..5/1) dom(31)}, {dates(2/29) doy(1..59)}", []int{8, 36}, [][]int{{9, 26}, {37, 49}}},
+		{"{dom(31) dates(2/1..2/28)}, dom(1)", []int{0}, [][]int{{1, 9}}},
+	}
+
+	for _, test := range tests {
+		_, err := New(test.format, Strict())
+		unsat, ok := err.(*UnsatisfiableError)
+		if !ok {
+			t.Errorf("%s: expected an UnsatisfiableError, got %v", test.format, err)
+			continue
+		}
+
+		if unsat.Index() != test.expressions[0][0] {
+			t.Errorf("%s: expected index %d, got %d", test.format, test.expressions[0][0], unsat.Index())
+		}
+
+		contradictions := unsat.Contradictions()
+		if len(contradictions) != len(test.groups) {
+			t.Errorf("%s: expected %d contradictions, got %d: %s", test.format, len(test.groups), len(contradictions), err)
+			continue
+		}
+
+		for i, c := range contradictions {
+			if c.GroupIndex != test.groups[i] || !reflect.DeepEqual(c.ExpressionIndexes, test.expressions[i]) {
+				t.Errorf("%s: expected group %d with expressions %v, got group %d with expressions %v",
+					test.format, test.groups[i], test.expressions[i], c.GroupIndex, c.ExpressionIndexes)
+			}
+		}
+
+		// contradictions are only reported in strict mode
+		if _, err := New(test.format); err != nil {
+			t.Errorf("%s: unexpected error without the Strict option: %s", test.format, err)
+		}
+	}
+}
+
+func TestStrictAcceptsSatisfiableSchedules(t *testing.T) {
+	formats := []string{
+		"dates(2/29)",
+		"dom(31) months(feb, mar)",
+		"dow(mon) dates(2024/1/1)",
+		"dow(fri#5) months(feb)",
+		"doy(366) dates(12/31)",
+	}
+
+	for _, format := range formats {
+		if _, err := New(format, Strict()); err != nil {
+			t.Errorf("%s: %s", format, err)
+		}
+	}
+}
+
+// TestSatisfiabilityMatchesExhaustiveScan checks isSatisfiable, which only searches one year of each kind, against a
+// search of every day in the 400 year cycle.
+func TestSatisfiabilityMatchesExhaustiveScan(t *testing.T) {
+	pools := [][]string{
+		{"dow(mon)", "dow(fri#5)", "dow(tue#-1)", "dow(sat..sun)", "dow(!mon..fri)"},
+		{"dom(31)", "dom(29)", "dom(-1)", "dom(1..7)", "dom(!1..28)"},
+		{"doy(366)", "doy(60)", "doy(-1)", "doy(1..59)"},
+		{"weeks(53)", "weeks(1)", "weeks(-1)", "weeks(52..53)"},
+		{"months(feb)", "months(jan)", "months(dec)", "months(!jan..nov)"},
+		{"dates(2/29)", "dates(12/31)", "dates(12/30..1/2)", "dates(2/28..3/1%2)", "dates(2100/2/28..2100/3/1)"},
+		{"years(2100)", "years(2000..2004)", "years(*%4)", "years(!1900..2199)"},
+		{"bday(23)", "bday(-1)", "bday(1, from 31)"},
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 150 {
+		var expressions []string
+		for _, i := range rng.Perm(len(pools))[:2+rng.IntN(3)] {
+			expressions = append(expressions, pools[i][rng.IntN(len(pools[i]))])
+		}
+
+		format := strings.Join(expressions, " ")
+		sch, err := New(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		impl := sch.(*scheduleImpl)
+		group := impl.ir.Groups[0]
+		if impl.isSatisfiable(group) != exhaustivelySatisfiable(impl, group) {
+			t.Errorf("%s: expected isSatisfiable to be %t", format, !impl.isSatisfiable(group))
+		}
+	}
+}
+
+func exhaustivelySatisfiable(s *scheduleImpl, group *internals.IrGroup) bool {
+	for date := time.Date(satisfiabilityFirstYear, 1, 1, 0, 0, 0, 0, time.UTC); date.Year() <= satisfiabilityLastYear; date = date.AddDate(0, 0, 1) {
+		if s.isApplicableDate(group, date) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,7 +2,9 @@ package schyntax
 
 import (
 	"github.com/schyntax/go-schyntax/internals"
+	"iter"
 	"math"
+	"math/bits"
 	"time"
 )
 
@@ -12,6 +14,45 @@ type Schedule interface {
 	NextAfter(after time.Time) (time.Time, error)
 	Previous() (time.Time, error)
 	PreviousAtOrBefore(atOrBefore time.Time) (time.Time, error)
+	// NextAtOrAfter returns the first event which is at or after atOrAfter.
+	NextAtOrAfter(atOrAfter time.Time) (time.Time, error)
+	// PreviousBefore returns the last event which is before before.
+	PreviousBefore(before time.Time) (time.Time, error)
+	// Location returns the location whose wall clock time the schedule is evaluated against.
+	Location() *time.Location
+
+	// ForKey returns a copy of the schedule whose jitter is derived from key, such as a hostname or job name. Every
+	// event of a group with a jitter expression is delayed by the same offset for the same key. The schedule returned
+	// by New uses the empty key.
+	ForKey(key string) Schedule
+
+	// Occurrences returns an iterator over every event which is at or after from, and before to, in chronological order.
+	Occurrences(from, to time.Time) iter.Seq[time.Time]
+	// OccurrencesBackward is the same as Occurrences, except that the events are in reverse chronological order.
+	OccurrencesBackward(from, to time.Time) iter.Seq[time.Time]
+	// ListOccurrences returns the first max events from Occurrences. If max is zero or less, every event is returned.
+	ListOccurrences(from, to time.Time, max int) []time.Time
+	// ListOccurrencesBackward returns the first max events from OccurrencesBackward. If max is zero or less, every event
+	// is returned.
+	ListOccurrencesBackward(from, to time.Time, max int) []time.Time
+
+	// NextN returns the first n events after after, in chronological order. The search of each group is resumed from
+	// its previous event, so this is much cheaper than calling NextAfter n times. Fewer than n events are returned if
+	// the search goes the schedule's horizon of days without finding the next one.
+	NextN(after time.Time, n int) []time.Time
+	// PreviousN is the same as NextN, except that it returns the last n events at or before atOrBefore, in reverse
+	// chronological order.
+	PreviousN(atOrBefore time.Time, n int) []time.Time
+
+	// Windows returns a view of the schedule as intervals of time, in which consecutive matching units are coalesced,
+	// so that "h(2..<4)" is a single window from 02:00 to 04:00.
+	Windows() *Windows
+
+	// Cron returns crontab lines (minute, hour, day of month, month and day of week) which together fire at the same
+	// times as the schedule, in the schedule's location. Each group becomes one or more lines, and exclusions are
+	// expanded into explicit lists. A *CronExportError is returned if the schedule uses a construct which cron can't
+	// express.
+	Cron() ([]string, error)
 }
 
 var _ Schedule = &scheduleImpl{}
@@ -19,41 +60,97 @@ var _ Schedule = &scheduleImpl{}
 type scheduleImpl struct {
 	originalText string
 	ir           *internals.IrProgram
+	loc          *time.Location
+	horizon      int
+	key          string
+	calendars    map[string]Calendar // the calendars referred to by cal expressions, by name
+	weekend      uint8               // the days of the week which aren't business days, where bit n is time.Weekday(n)
+	holidays     Calendar            // the days which aren't business days, or nil if there are none
 }
 
-func New(schedule string) (sch Schedule, err error) {
-	defer func() {
-		if e := recover(); e != nil {
-			sch = nil
-			switch e.(type) {
-			case string:
-				err = newInternalError(e.(string), schedule)
-			default:
-				if er, ok := e.(error); ok {
-					err = er
-				} else {
-					panic(e) // no idea what was passed to panic, just pass it along
-				}
-			}
-		}
-	}()
+func New(schedule string, options ...Option) (sch Schedule, err error) {
+	defer recoverError(schedule, &err)
+
+	opts := defaultOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+
+	var ast *internals.ProgramNode
+	if opts.allErrors {
+		ast = parseReportingAllErrors(schedule, opts.isCalendar)
+	} else {
+		ast = parse(schedule, opts.isCalendar)
+	}
 
+	ir := internals.CompileAst(ast)
+
+	impl := &scheduleImpl{schedule, ir, opts.location, opts.horizon, "", calendarsOf(&opts, ir), opts.weekend, opts.holidays}
+	if opts.strict {
+		impl.assertSatisfiable(ast)
+	}
+
+	return impl, nil
+}
+
+// parse parses and validates a schedule. It panics if the schedule is invalid, so callers must defer recoverError. If
+// isCalendar is nil, the names of calendars aren't checked.
+func parse(schedule string, isCalendar func(name string) bool) *internals.ProgramNode {
 	parser := internals.NewParser(schedule)
 	ast := parser.Parse()
 
-	validator := internals.Validator{schedule, ast}
+	validator := internals.Validator{Input: schedule, Program: ast, IsCalendar: isCalendar}
 	validator.AssertValid()
 
-	ir := internals.CompileAst(ast)
+	return ast
+}
 
-	sch = &scheduleImpl{schedule, ir}
-	return
+// parseReportingAllErrors is the same as parse, except that it panics with an *internals.ParseErrorList of every error
+// in the schedule.
+func parseReportingAllErrors(schedule string, isCalendar func(name string) bool) *internals.ProgramNode {
+	errors := internals.NewParseErrorList(schedule)
+	parser := internals.NewRecoveringParser(schedule, errors)
+	ast := parser.Parse()
+
+	validator := internals.Validator{Input: schedule, Program: ast, Errors: errors, IsCalendar: isCalendar}
+	validator.AssertValid()
+
+	if errors.Len() > 0 {
+		panic(errors)
+	}
+
+	return ast
+}
+
+// recoverError converts a panic raised while parsing, validating or compiling input into an error. It must be deferred.
+func recoverError(input string, err *error) {
+	if e := recover(); e != nil {
+		switch e.(type) {
+		case string:
+			*err = newInternalError(e.(string), input)
+		default:
+			if er, ok := e.(error); ok {
+				*err = er
+			} else {
+				panic(e) // no idea what was passed to panic, just pass it along
+			}
+		}
+	}
+}
+
+// NewInLocation is shorthand for New(schedule, InLocation(loc)).
+func NewInLocation(schedule string, loc *time.Location) (Schedule, error) {
+	return New(schedule, InLocation(loc))
 }
 
 func (s *scheduleImpl) OriginalText() string {
 	return s.originalText
 }
 
+func (s *scheduleImpl) Location() *time.Location {
+	return s.loc
+}
+
 func (s *scheduleImpl) Next() (time.Time, error) {
 	return s.getEvent(time.Now(), searchModeAfter)
 }
@@ -70,6 +167,16 @@ func (s *scheduleImpl) PreviousAtOrBefore(atOrBefore time.Time) (time.Time, erro
 	return s.getEvent(atOrBefore, searchModeAtOrBefore)
 }
 
+// Events are always on a whole millisecond, so moving the start of a search back by a nanosecond turns an "after"
+// search into an "at or after" one, and an "at or before" search into a "before" one.
+func (s *scheduleImpl) NextAtOrAfter(atOrAfter time.Time) (time.Time, error) {
+	return s.getEvent(atOrAfter.Add(-time.Nanosecond), searchModeAfter)
+}
+
+func (s *scheduleImpl) PreviousBefore(before time.Time) (time.Time, error) {
+	return s.getEvent(before.Add(-time.Nanosecond), searchModeAtOrBefore)
+}
+
 type searchMode int8
 
 const (
@@ -77,12 +184,29 @@ const (
 	searchModeAfter
 )
 
+// isNearer returns true if a comes before b in the search direction.
+func (m searchMode) isNearer(a, b time.Time) bool {
+	if m == searchModeAfter {
+		return a.Before(b)
+	}
+
+	return a.After(b)
+}
+
+func (m searchMode) direction() SearchDirection {
+	if m == searchModeAfter {
+		return SearchForward
+	}
+
+	return SearchBackward
+}
+
 func (s *scheduleImpl) getEvent(start time.Time, mode searchMode) (result time.Time, err error) {
-	start = start.UTC()
+	start = start.In(s.loc)
 	found := false
 
 	for _, group := range s.ir.Groups {
-		if e, good := tryGetGroupEvent(group, start, mode); good {
+		if e, good := s.tryGetGroupEvent(group, start, mode); good {
 			if !found || (mode == searchModeAfter && e.Before(result)) || (mode == searchModeAtOrBefore && e.After(result)) {
 				result = e
 				found = true
@@ -91,221 +215,416 @@ func (s *scheduleImpl) getEvent(start time.Time, mode searchMode) (result time.T
 	}
 
 	if !found {
-		err = &ValidTimeNotFoundError{s.originalText}
+		err = &ValidTimeNotFoundError{s.originalText, s.horizon, mode.direction()}
+	}
+
+	return
+}
+
+func (s *scheduleImpl) tryGetGroupEvent(group *internals.IrGroup, start time.Time, mode searchMode) (result time.Time, found bool) {
+	for e := range s.groupEvents(group, start, mode) {
+		return e, true
 	}
 
 	return
 }
 
-func tryGetGroupEvent(group *internals.IrGroup, start time.Time, mode searchMode) (result time.Time, found bool) {
+// groupEvents returns an iterator over the events of a group, starting from start and moving in the direction of the
+// search mode. Iteration ends once the search has gone the schedule's horizon of days without finding an event. The
+// group's jitter is applied by searching for the events it would have without jitter, relative to a start which is
+// moved back by the same offset.
+func (s *scheduleImpl) groupEvents(group *internals.IrGroup, start time.Time, mode searchMode) iter.Seq[time.Time] {
+	offset := s.jitterOffset(group)
+	if offset == 0 {
+		return func(yield func(time.Time) bool) {
+			s.searchGroup(group, start, mode, yield)
+		}
+	}
+
+	return func(yield func(time.Time) bool) {
+		s.searchGroup(group, start.Add(-offset), mode, func(e time.Time) bool {
+			return yield(e.Add(offset))
+		})
+	}
+}
+
+func (s *scheduleImpl) searchGroup(group *internals.IrGroup, start time.Time, mode searchMode, yield func(time.Time) bool) {
 	after := mode == searchModeAfter
 	inc := 1 // used for incrementing values up or down depending on the direction we're searching
 	initHour := 0
 	initMinute := 0
 	initSecond := 0
+	initMillisecond := 0
 
 	if !after {
 		inc = -1
 		initHour = 23
 		initMinute = 59
 		initSecond = 59
+		initMillisecond = 999
+	}
+
+	var event time.Time
+
+	// The search walks wall clock time in the schedule's location. Wall clock times are represented in UTC so that
+	// date arithmetic isn't affected by daylight saving transitions, and are only converted into real instants once
+	// they match the schedule. Sub-second parts of start are kept, so that events are only compared against it to the
+	// millisecond.
+	var wallStart time.Time
+	if after {
+		wallStart = wallClock(start)
+	} else {
+		wallStart = wallClockAtOrBefore(start)
 	}
 
-	var hourCount, minuteCount, secondCount int
+	// each event must be beyond the previous one, which also filters out wall clock times that a daylight saving
+	// transition moved to the wrong side of start, or onto the same instant as another wall clock time
+	previous := start
+	previousIsEvent := false
+	lastEventDay := 0
 
-	// todo: make the length of the search configurable
-	for d := 0; d < 4*365; d++ {
+	for d := 0; d-lastEventDay < s.horizon; d++ {
 		var date time.Time
-		var hour, minute, second int
+		var hour, minute, second, millisecond int
 		if d == 0 {
 			// "after" events must be in the future
-			if after {
-				date = start.Add(time.Second)
-			} else {
-				date = start
-			}
+			date = wallStart
 
 			hour = date.Hour()
 			minute = date.Minute()
 			second = date.Second()
+			millisecond = date.Nanosecond() / int(time.Millisecond)
+			if after {
+				millisecond++
+			}
 		} else {
-			date = start.AddDate(0, 0, d*inc)
+			date = wallStart.AddDate(0, 0, d*inc)
 
 			hour = initHour
 			minute = initMinute
 			second = initSecond
+			millisecond = initMillisecond
 		}
 
 		year := date.Year()
 		month := int(date.Month())
-		dayOfYear := date.YearDay()
-		dayOfWeek := int(date.Weekday()) + 1 // Weekday is zero-indexed
 		dayOfMonth := date.Day()
 
-		// check if today is an applicable date
-		if group.HasDates() {
-			applicable := false
-			for _, r := range group.Dates {
-				if inDateRange(r, year, month, dayOfMonth) {
-					applicable = true
-					break
+		if !group.IsApplicableYear(year) {
+			// skip the rest of the year, so that excluded years don't have to be searched one day at a time
+			d += daysLeftInYear(date, after)
+			goto CONTINUE_DATE_LOOP
+		}
+
+		if !s.isApplicableDate(group, date) {
+			goto CONTINUE_DATE_LOOP
+		}
+
+		// if we've gotten this far, then today is an applicable day. The masks let us jump straight to each applicable
+		// hour, minute, second and millisecond. Once the search moves past the starting hour, minute or second, the units
+		// below it are searched in full.
+		for h := nextSetBit(uint64(group.HoursMask), hour, after); h != -1; h = nextSetBit(uint64(group.HoursMask), h+inc, after) {
+			if h != hour {
+				minute = initMinute
+				second = initSecond
+				millisecond = initMillisecond
+			}
+
+			for m := nextSetBit(group.MinutesMask, minute, after); m != -1; m = nextSetBit(group.MinutesMask, m+inc, after) {
+				if m != minute {
+					second = initSecond
+					millisecond = initMillisecond
+				}
+
+				for sec := nextSetBit(group.SecondsMask, second, after); sec != -1; sec = nextSetBit(group.SecondsMask, sec+inc, after) {
+					if sec != second {
+						millisecond = initMillisecond
+					}
+
+					for ms := group.MillisecondsMask.Next(millisecond, after); ms != -1; ms = group.MillisecondsMask.Next(ms+inc, after) {
+						// we've found an event
+						event = localTime(year, month, dayOfMonth, h, m, sec, s.loc).Add(time.Duration(ms) * time.Millisecond)
+						if isBeyond(event, previous, after, previousIsEvent) {
+							if !yield(event) {
+								return
+							}
+
+							previous = event
+							previousIsEvent = true
+							lastEventDay = d
+						}
+					}
 				}
 			}
+		}
 
-			if !applicable {
-				goto CONTINUE_DATE_LOOP
+	CONTINUE_DATE_LOOP:
+	}
+}
+
+// isApplicableDate returns true if every date level rule in the group matches the date (a wall clock time).
+func (s *scheduleImpl) isApplicableDate(group *internals.IrGroup, date time.Time) bool {
+	year := date.Year()
+	month := int(date.Month())
+	dayOfYear := date.YearDay()
+	dayOfWeek := int(date.Weekday()) + 1 // Weekday is zero-indexed
+	dayOfMonth := date.Day()
+
+	if !group.IsApplicableYear(year) {
+		return false
+	}
+
+	// check if date is in an applicable month
+	if group.MonthsMask&(1<<uint(month)) == 0 {
+		return false
+	}
+
+	// check if today is an applicable date
+	if group.HasDates() {
+		applicable := false
+		for _, r := range group.Dates {
+			if inDateRange(r, year, month, dayOfMonth) {
+				applicable = true
+				break
 			}
 		}
 
-		if group.HasDatesExcluded() {
-			for _, r := range group.DatesExcluded {
-				if inDateRange(r, year, month, dayOfMonth) {
-					goto CONTINUE_DATE_LOOP
-				}
+		if !applicable {
+			return false
+		}
+	}
+
+	if group.HasDatesExcluded() {
+		for _, r := range group.DatesExcluded {
+			if inDateRange(r, year, month, dayOfMonth) {
+				return false
 			}
 		}
+	}
 
-		// check if date is an applicable day of year
-		if group.HasDaysOfYear() {
-			applicable := false
-			for _, r := range group.DaysOfYear {
-				if inDayOfYearRange(r, year, dayOfYear) {
-					applicable = true
-					break
-				}
+	// check if date is an applicable day of year
+	if group.HasDaysOfYear() {
+		applicable := false
+		for _, r := range group.DaysOfYear {
+			if inDayOfYearRange(r, year, dayOfYear) {
+				applicable = true
+				break
 			}
+		}
 
-			if !applicable {
-				goto CONTINUE_DATE_LOOP
+		if !applicable {
+			return false
+		}
+	}
+
+	if group.HasDaysOfYearExcluded() {
+		for _, r := range group.DaysOfYearExcluded {
+			if inDayOfYearRange(r, year, dayOfYear) {
+				return false
+			}
+		}
+	}
+
+	// check if date is an applicable day of month
+	if group.HasDaysOfMonth() {
+		applicable := false
+		for _, r := range group.DaysOfMonth {
+			if inDayOfMonthRange(r, year, month, dayOfMonth) {
+				applicable = true
+				break
 			}
 		}
 
-		if group.HasDaysOfYearExcluded() {
-			for _, r := range group.DaysOfYearExcluded {
-				if inDayOfYearRange(r, year, dayOfYear) {
-					goto CONTINUE_DATE_LOOP
-				}
+		if !applicable {
+			return false
+		}
+	}
+
+	if group.HasDaysOfMonthExcluded() {
+		for _, r := range group.DaysOfMonthExcluded {
+			if inDayOfMonthRange(r, year, month, dayOfMonth) {
+				return false
 			}
 		}
+	}
 
-		// check if date is an applicable day of month
-		if group.HasDaysOfMonth() {
+	// check if date is in an applicable ISO week
+	if group.HasWeeks() || group.HasWeeksExcluded() {
+		isoYear, week := date.ISOWeek()
+		if group.HasWeeks() {
 			applicable := false
-			for _, r := range group.DaysOfMonth {
-				if inDayOfMonthRange(r, year, month, dayOfMonth) {
+			for _, r := range group.Weeks {
+				if inWeekRange(r, isoYear, week) {
 					applicable = true
 					break
 				}
 			}
 
 			if !applicable {
-				goto CONTINUE_DATE_LOOP
+				return false
 			}
 		}
 
-		if group.HasDaysOfMonthExcluded() {
-			for _, r := range group.DaysOfMonthExcluded {
-				if inDayOfMonthRange(r, year, month, dayOfMonth) {
-					goto CONTINUE_DATE_LOOP
-				}
+		for _, r := range group.WeeksExcluded {
+			if inWeekRange(r, isoYear, week) {
+				return false
 			}
 		}
+	}
 
-		// check if date is an applicable day of week
-		if group.HasDaysOfWeek() && !inRule(7, group.DaysOfWeek, dayOfWeek) {
-			goto CONTINUE_DATE_LOOP
+	// check if date is an applicable day of week (ranges using the # operator can't be represented by the masks)
+	dayOfWeekBit := uint8(1) << uint(dayOfWeek)
+	if group.DaysOfWeekMask&dayOfWeekBit == 0 && !(group.HasDaysOfWeekNth && inDayOfWeekRule(group.DaysOfWeek, year, month, dayOfMonth, dayOfWeek)) {
+		return false
+	}
+
+	if group.DaysOfWeekExcludedMask&dayOfWeekBit != 0 || (group.HasDaysOfWeekNth && inDayOfWeekRule(group.DaysOfWeekExcluded, year, month, dayOfMonth, dayOfWeek)) {
+		return false
+	}
+
+	// check if date is in an applicable period
+	if group.HasPeriods() {
+		applicable := false
+		for _, p := range group.Periods {
+			if p.Contains(year, month, dayOfMonth) {
+				applicable = true
+				break
+			}
 		}
 
-		if group.HasDaysOfWeekExcluded() && inRule(7, group.DaysOfWeekExcluded, dayOfWeek) {
-			goto CONTINUE_DATE_LOOP
+		if !applicable {
+			return false
 		}
+	}
 
-		// if we've gotten this far, then today is an applicable day, let's keep going with hour checks
-		if after {
-			hourCount = 24 - hour
-		} else {
-			hourCount = hour + 1
+	// check if date is an applicable business day. Only business days match a group with a businessdays expression,
+	// even if it only has exclusions.
+	if group.HasBusinessDays() || group.HasBusinessDaysExcluded() {
+		if !s.isBusinessDay(year, month, dayOfMonth) {
+			return false
 		}
 
-		for hourCount > 0 {
-			if group.HasHours() && !inRule(24, group.Hours, hour) {
-				goto CONTINUE_HOUR_LOOP
-			}
+		if group.HasBusinessDays() && !s.inBusinessDayRule(group.BusinessDays, year, month, dayOfMonth) {
+			return false
+		}
 
-			if group.HasHoursExcluded() && inRule(24, group.HoursExcluded, hour) {
-				goto CONTINUE_HOUR_LOOP
-			}
+		if s.inBusinessDayRule(group.BusinessDaysExcluded, year, month, dayOfMonth) {
+			return false
+		}
+	}
 
-			// if we've gotten here, the date and hour are valid. Let's check for minutes
-			if after {
-				minuteCount = 60 - minute
-			} else {
-				minuteCount = minute + 1
-			}
+	// check if date is in an applicable calendar
+	if group.HasCalendars() && !s.inCalendars(group.Calendars, year, month, dayOfMonth) {
+		return false
+	}
 
-			for minuteCount > 0 {
-				if group.HasMinutes() && !inRule(60, group.Minutes, minute) {
-					goto CONTINUE_MINUTE_LOOP
-				}
+	if group.HasCalendarsExcluded() && s.inCalendars(group.CalendarsExcluded, year, month, dayOfMonth) {
+		return false
+	}
 
-				if group.HasMinutesExcluded() && inRule(60, group.MinutesExcluded, minute) {
-					goto CONTINUE_MINUTE_LOOP
-				}
+	return true
+}
 
-				// check for valid seconds
-				if after {
-					secondCount = 60 - second
-				} else {
-					secondCount = second + 1
-				}
+// daysLeftInYear returns the number of days after date, in the search direction, which are in the same year.
+func daysLeftInYear(date time.Time, after bool) int {
+	if after {
+		return internals.DaysInYear(date.Year()) - date.YearDay()
+	}
 
-				for secondCount > 0 {
-					if group.HasSeconds() && !inRule(60, group.Seconds, second) {
-						goto CONTINUE_SECOND_LOOP
-					}
+	return date.YearDay() - 1
+}
 
-					if group.HasSecondsExcluded() && inRule(60, group.SecondsExcluded, second) {
-						goto CONTINUE_SECOND_LOOP
-					}
+// isBeyond returns true if event comes after previous in the search direction. An event may be equal to the start of an
+// "at or before" search, but never to a previous event.
+func isBeyond(event, previous time.Time, after, previousIsEvent bool) bool {
+	if after {
+		return event.After(previous)
+	}
 
-					// we've found our event
-					result = time.Date(year, time.Month(month), dayOfMonth, hour, minute, second, 0, time.UTC)
-					found = true
-					return
+	return event.Before(previous) || (!previousIsEvent && event.Equal(previous))
+}
 
-				CONTINUE_SECOND_LOOP:
-					secondCount--
-					second += inc
-				}
+// wallClock returns the wall clock time of t in its own location, represented in UTC.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
 
-			CONTINUE_MINUTE_LOOP:
-				minuteCount--
-				minute += inc
-				second = initSecond
-			}
+// wallClockAtOrBefore returns the latest wall clock time whose first occurrence is at or before t. This is only
+// different from wallClock(t) when t is during the second occurrence of a wall clock time repeated by a daylight
+// saving transition, in which case it is the wall clock time of t as it would read before the transition.
+func wallClockAtOrBefore(t time.Time) time.Time {
+	wall := wallClock(t)
+	first := localTime(wall.Year(), int(wall.Month()), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), t.Location())
+
+	_, firstOffset := first.Zone()
+	_, offset := t.Zone()
+	if firstOffset == offset {
+		return wall
+	}
 
-		CONTINUE_HOUR_LOOP:
-			hourCount--
-			hour += inc
-			minute = initMinute
-			second = initSecond
+	return wall.Add(time.Duration(firstOffset-offset) * time.Second)
+}
+
+// localTime returns the instant at which the wall clock time occurs in loc. Wall clock times which are skipped by a
+// daylight saving transition resolve to the instant of the transition. Wall clock times which are repeated resolve to
+// their first occurrence.
+func localTime(year, month, day, hour, minute, second int, loc *time.Location) time.Time {
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, loc)
+	if loc == time.UTC {
+		return t
+	}
+
+	wall := time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
+	if actual := wallClock(t); !actual.Equal(wall) {
+		// the wall clock time doesn't exist, so use the transition which skipped it
+		zoneStart, zoneEnd := t.ZoneBounds()
+		if actual.Before(wall) {
+			return zoneEnd
 		}
 
-	CONTINUE_DATE_LOOP:
+		return zoneStart
 	}
 
-	// we didn't find an applicable date
-	return
+	// check whether the same wall clock time also occurred before the transition at the start of this zone
+	zoneStart, _ := t.ZoneBounds()
+	if !zoneStart.IsZero() {
+		_, offset := t.Zone()
+		_, prevOffset := zoneStart.Add(-time.Nanosecond).Zone()
+		earlier := t.Add(time.Duration(offset-prevOffset) * time.Second)
+		if earlier.Before(zoneStart) && wallClock(earlier).Equal(wall) {
+			return earlier
+		}
+	}
+
+	return t
 }
 
-func inRule(lengthOfUnit int, ranges []*internals.IrIntegerRange, value int) bool {
-	for _, r := range ranges {
-		if inIntegerRange(r, value, lengthOfUnit) {
-			return true
+// nextSetBit returns the position of the first set bit in mask which is at or after from, or at or before from when
+// forward is false. Returns -1 if there is no such bit.
+func nextSetBit(mask uint64, from int, forward bool) int {
+	if forward {
+		if from > 63 {
+			return -1
 		}
+
+		m := mask >> uint(from)
+		if m == 0 {
+			return -1
+		}
+
+		return from + bits.TrailingZeros64(m)
 	}
 
-	return false
+	if from < 0 {
+		return -1
+	}
+
+	m := mask << uint(63-from)
+	if m == 0 {
+		return -1
+	}
+
+	return from - bits.LeadingZeros64(m)
 }
 
 func inDateRange(r *internals.IrDateRange, year, month, dayOfMonth int) bool {
@@ -444,7 +763,29 @@ func inDayOfYearRange(r *internals.IrIntegerRange, year, dayOfYear int) bool {
 	}
 
 	daysInPreviousYear := internals.DaysInYear(year - 1)
-	return inIntegerRange(r, dayOfYear, daysInPreviousYear)
+	return r.Contains(dayOfYear, daysInPreviousYear)
+}
+
+// inWeekRange returns true if the ISO week of isoYear is in the range. Negative weeks count back from the last week of
+// the year, which is either week 52 or week 53.
+func inWeekRange(r *internals.IrIntegerRange, isoYear, week int) bool {
+	if r.Start < 0 || (r.IsRange && r.End < 0) {
+		weeksInYear := internals.WeeksInISOYear(isoYear)
+
+		revisedStart := r.Start
+		if revisedStart < 0 {
+			revisedStart = weeksInYear + revisedStart + 1
+		}
+
+		revisedEnd := r.End
+		if revisedEnd < 0 {
+			revisedEnd = weeksInYear + revisedEnd + 1
+		}
+
+		r = r.CloneWithRevisedRange(revisedStart, revisedEnd)
+	}
+
+	return r.Contains(week, internals.WeeksInISOYear(isoYear-1))
 }
 
 func inDayOfMonthRange(r *internals.IrIntegerRange, year, month, dayOfMonth int) bool {
@@ -465,42 +806,35 @@ func inDayOfMonthRange(r *internals.IrIntegerRange, year, month, dayOfMonth int)
 		r = r.CloneWithRevisedRange(revisedStart, revisedEnd)
 	}
 
-	return inIntegerRange(r, dayOfMonth, daysInPreviousMonth(year, month))
+	return r.Contains(dayOfMonth, daysInPreviousMonth(year, month))
 }
 
-func inIntegerRange(r *internals.IrIntegerRange, value, lengthOfUnit int) bool {
-	if !r.IsRange {
-		return value == r.Start
+func inDayOfWeekRule(ranges []*internals.IrIntegerRange, year, month, dayOfMonth, dayOfWeek int) bool {
+	for _, r := range ranges {
+		if inDayOfWeekRange(r, year, month, dayOfMonth, dayOfWeek) {
+			return true
+		}
 	}
 
-	if r.IsHalfOpen && value == r.End {
+	return false
+}
+
+func inDayOfWeekRange(r *internals.IrIntegerRange, year, month, dayOfMonth, dayOfWeek int) bool {
+	if !r.Contains(dayOfWeek, 7) {
 		return false
 	}
 
-	if r.IsSplit { // range spans across the max value and loops back around
-		if value <= r.End || value >= r.Start {
-			if r.HasInterval {
-				if value >= r.Start {
-					return (value-r.Start)%r.Interval == 0
-				}
-
-				return (value+lengthOfUnit-r.Start)%r.Interval == 0
-			}
-
-			return true
-		}
-
-	} else { // not a split range (easier case)
-		if value >= r.Start && value <= r.End {
-			if r.HasInterval {
-				return (value-r.Start)%r.Interval == 0
-			}
+	if !r.HasNth {
+		return true
+	}
 
-			return true
-		}
+	if r.Nth > 0 {
+		return (dayOfMonth-1)/7+1 == r.Nth
 	}
 
-	return false
+	// negative occurrences count back from the end of the month, the same way negative days of the month do
+	daysInMonth := internals.DaysInMonth(year, month)
+	return (daysInMonth-dayOfMonth)/7+1 == -r.Nth
 }
 
 func daysInPreviousMonth(year, month int) int {
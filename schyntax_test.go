@@ -2,6 +2,7 @@ package schyntax
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
@@ -61,7 +62,7 @@ func runTest(t *testing.T, check *check) {
 				if parseError.Index() == *check.ParseErrorIndex {
 					logNonVerbose(t, "Expected Parse Error ✓")
 				} else {
-					t.Errorf("Wrong parse error index. Expected: %d. Actual: %d.\n", *check.ParseErrorIndex, parseError.Error())
+					t.Errorf("Wrong parse error index. Expected: %d. Actual: %d.\n", *check.ParseErrorIndex, parseError.Index())
 				}
 				return
 			}
@@ -115,3 +116,420 @@ func logNonVerbose(t *testing.T, msg string) {
 		t.Log(msg)
 	}
 }
+
+type locationCheck struct {
+	format string
+	date   string
+	prev   string
+	next   string
+}
+
+func TestLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is unavailable: ", err)
+	}
+
+	checks := []locationCheck{
+		// ordinary wall clock evaluation
+		{"hours(9..<17)", "2025-06-02T08:30:00-04:00", "2025-06-01T16:00:00-04:00", "2025-06-02T09:00:00-04:00"},
+		{"dow(mon) hours(9)", "2025-06-02T08:30:00-04:00", "2025-05-26T09:00:00-04:00", "2025-06-02T09:00:00-04:00"},
+		// spring forward: 02:00 to 02:59 local are skipped and fire at 03:00 EDT
+		{"h(2) min(30)", "2025-03-09T01:00:00-05:00", "2025-03-08T02:30:00-05:00", "2025-03-09T03:00:00-04:00"},
+		{"h(2) min(30)", "2025-03-09T03:00:00-04:00", "2025-03-09T03:00:00-04:00", "2025-03-10T02:30:00-04:00"},
+		{"min(*%15)", "2025-03-09T01:50:00-05:00", "2025-03-09T01:45:00-05:00", "2025-03-09T03:00:00-04:00"},
+		{"min(*%15)", "2025-03-09T03:00:00-04:00", "2025-03-09T03:00:00-04:00", "2025-03-09T03:15:00-04:00"},
+		// fall back: 01:00 to 01:59 local are repeated and only fire during the first occurrence
+		{"h(1) min(30)", "2025-11-02T01:45:00-05:00", "2025-11-02T01:30:00-04:00", "2025-11-03T01:30:00-05:00"},
+		{"min(0)", "2025-11-02T01:30:00-05:00", "2025-11-02T01:00:00-04:00", "2025-11-02T02:00:00-05:00"},
+		{"min(0)", "2025-11-02T01:30:00-04:00", "2025-11-02T01:00:00-04:00", "2025-11-02T02:00:00-05:00"},
+	}
+
+	for _, c := range checks {
+		sch, err := NewInLocation(c.format, loc)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		date := parseTestTime(t, c.date)
+		if prev, err := sch.PreviousAtOrBefore(date); err != nil {
+			t.Error(err)
+		} else if !prev.Equal(parseTestTime(t, c.prev)) || prev.Location() != loc {
+			t.Errorf("%s: previous from %s. Expected: %s, Actual: %s", c.format, c.date, c.prev, prev)
+		}
+
+		if next, err := sch.NextAfter(date); err != nil {
+			t.Error(err)
+		} else if !next.Equal(parseTestTime(t, c.next)) || next.Location() != loc {
+			t.Errorf("%s: next after %s. Expected: %s, Actual: %s", c.format, c.date, c.next, next)
+		}
+	}
+}
+
+func parseTestTime(t *testing.T, value string) time.Time {
+	tm, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tm
+}
+
+func TestMonths(t *testing.T) {
+	checks := []*check{
+		newCheck(t, "months(jan..mar)", "2025-06-15T10:00:00Z", "2025-03-31T00:00:00Z", "2026-01-01T00:00:00Z"),
+		newCheck(t, "month(MARCH, 7)", "2025-06-15T10:00:00Z", "2025-03-31T00:00:00Z", "2025-07-01T00:00:00Z"),
+		newCheck(t, "mon(nov..feb) dom(1)", "2025-06-15T10:00:00Z", "2025-02-01T00:00:00Z", "2025-11-01T00:00:00Z"),
+		newCheck(t, "mon(nov..feb) dom(1)", "2025-12-15T10:00:00Z", "2025-12-01T00:00:00Z", "2026-01-01T00:00:00Z"),
+		newCheck(t, "months(*%3) dom(1)", "2025-06-15T10:00:00Z", "2025-04-01T00:00:00Z", "2025-07-01T00:00:00Z"),
+		newCheck(t, "months(!jul) dom(1)", "2025-07-15T10:00:00Z", "2025-06-01T00:00:00Z", "2025-08-01T00:00:00Z"),
+		newParseErrorCheck("months(13)", 7),
+		newParseErrorCheck("months(0)", 7),
+		newParseErrorCheck("months(mon)", 7),
+		newParseErrorCheck("dow(jan)", 4),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+}
+
+// newCheck creates a check from RFC 3339 strings. Empty prev or next strings mean no event is expected.
+func newCheck(t *testing.T, format, date, prev, next string) *check {
+	c := &check{Format: format, Date: parseTestTime(t, date)}
+	if prev != "" {
+		p := parseTestTime(t, prev)
+		c.Prev = &p
+	}
+
+	if next != "" {
+		n := parseTestTime(t, next)
+		c.Next = &n
+	}
+
+	return c
+}
+
+func newParseErrorCheck(format string, index int) *check {
+	return &check{Format: format, ParseErrorIndex: &index}
+}
+
+func TestNthDayOfWeek(t *testing.T) {
+	checks := []*check{
+		newCheck(t, "dow(tue#2)", "2025-06-15T10:00:00Z", "2025-06-10T00:00:00Z", "2025-07-08T00:00:00Z"),
+		newCheck(t, "dow(fri#-1)", "2025-06-15T10:00:00Z", "2025-05-30T00:00:00Z", "2025-06-27T00:00:00Z"),
+		newCheck(t, "dow(mon#5)", "2025-06-15T10:00:00Z", "2025-03-31T00:00:00Z", "2025-06-30T00:00:00Z"),
+		newCheck(t, "dow(mon#1, fri#-1) h(9)", "2025-06-15T10:00:00Z", "2025-06-02T09:00:00Z", "2025-06-27T09:00:00Z"),
+		newCheck(t, "dow(mon..fri, !mon#1)", "2025-06-03T10:00:00Z", "2025-06-03T00:00:00Z", "2025-06-04T00:00:00Z"),
+		newCheck(t, "dow(mon..fri, !mon#1)", "2025-06-02T10:00:00Z", "2025-05-30T00:00:00Z", "2025-06-03T00:00:00Z"),
+		newParseErrorCheck("dow(tue#6)", 8),
+		newParseErrorCheck("dow(tue#0)", 8),
+		newParseErrorCheck("dow(tue..thu#2)", 12),
+		newParseErrorCheck("dow(*#2)", 5),
+		newParseErrorCheck("dom(5#2)", 5),
+		newParseErrorCheck("dow(tue#)", 8),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+}
+
+func TestDateIntervals(t *testing.T) {
+	checks := []*check{
+		newCheck(t, "dates(3/1..3/31%10)", "2025-03-05T10:00:00Z", "2025-03-01T00:00:00Z", "2025-03-11T00:00:00Z"),
+		newCheck(t, "dates(3/1..<3/21%10)", "2025-03-15T10:00:00Z", "2025-03-11T00:00:00Z", "2026-03-01T00:00:00Z"),
+		newCheck(t, "dates(12/1%7)", "2025-12-10T10:00:00Z", "2025-12-08T00:00:00Z", "2025-12-15T00:00:00Z"),
+		newCheck(t, "dates(2025/1/1..2025/3/1%30)", "2025-02-15T10:00:00Z", "2025-01-31T00:00:00Z", ""),
+		newParseErrorCheck("dates(1/1..2/1%400)", 15),
+		newParseErrorCheck("dates(1/1..2/1%-1)", 15),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+}
+
+func TestSplitDateRanges(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// a range without years which spans January 1 matches at the end of one year and the start of the next
+	sch, err := New("dates(12/30..1/2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := sch.ListOccurrences(from.AddDate(1, 0, 0), to, 0)
+	if len(events) != 4 || events[0].Day() != 30 || events[3].Day() != 2 {
+		t.Errorf("Expected December 30 through January 2, got %v", events)
+	}
+
+	// a range with years is never split
+	sch, err = New("dates(2024/12/30..2025/1/2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events = sch.ListOccurrences(from, to, 0)
+	if len(events) != 4 || !events[0].Equal(time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)) || !events[3].Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected December 30, 2024 through January 2, 2025, got %v", events)
+	}
+}
+
+func TestMilliseconds(t *testing.T) {
+	checks := []*check{
+		newCheck(t, "ms(0, 500)", "2025-06-15T10:00:00.2Z", "2025-06-15T10:00:00Z", "2025-06-15T10:00:00.5Z"),
+		newCheck(t, "ms(0, 500)", "2025-06-15T10:00:00.5Z", "2025-06-15T10:00:00.5Z", "2025-06-15T10:00:01Z"),
+		newCheck(t, "ms(250) s(30)", "2025-06-15T10:00:00Z", "2025-06-15T09:59:30.25Z", "2025-06-15T10:00:30.25Z"),
+		newCheck(t, "ms(*%100) s(0) min(0) h(12)", "2025-06-15T12:00:00.95Z", "2025-06-15T12:00:00.9Z", "2025-06-16T12:00:00Z"),
+		newCheck(t, "ms(!0)", "2025-06-15T10:00:00.0005Z", "2025-06-15T09:59:59.999Z", "2025-06-15T10:00:00.001Z"),
+		newCheck(t, "h(9)", "2025-06-15T09:00:00.5Z", "2025-06-15T09:00:00Z", "2025-06-16T09:00:00Z"),
+		newParseErrorCheck("ms(1000)", 3),
+		newParseErrorCheck("ms(*%1000)", 5),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+}
+
+func TestYears(t *testing.T) {
+	checks := []*check{
+		newCheck(t, "years(2025..2030%2) dates(1/1)", "2026-06-01T00:00:00Z", "2025-01-01T00:00:00Z", "2027-01-01T00:00:00Z"),
+		newCheck(t, "years(!2026) dates(1/1)", "2025-06-01T00:00:00Z", "2025-01-01T00:00:00Z", "2027-01-01T00:00:00Z"),
+		newCheck(t, "years(2024%4) dates(7/4)", "2025-01-01T00:00:00Z", "2024-07-04T00:00:00Z", "2028-07-04T00:00:00Z"),
+		newCheck(t, "YEAR(2023..<2026) h(12)", "2024-12-31T13:00:00Z", "2024-12-31T12:00:00Z", "2025-01-01T12:00:00Z"),
+		newParseErrorCheck("years(1899)", 6),
+		newParseErrorCheck("years(2030..2025)", 6),
+		newParseErrorCheck("years(*%301)", 8),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+
+	// excluded years are skipped without searching each of their days
+	sch, _ := New("years(2150) dates(1/1)", SearchHorizon(100000))
+	if next, err := sch.NextAfter(parseTestTime(t, "2025-06-15T00:00:00Z")); err != nil || !next.Equal(parseTestTime(t, "2150-01-01T00:00:00Z")) {
+		t.Errorf("Expected 2150-01-01, got %s (%v)", next, err)
+	}
+
+	if prev, err := sch.PreviousAtOrBefore(parseTestTime(t, "2149-12-31T00:00:00Z")); err == nil {
+		t.Errorf("Expected no previous event, got %s", prev)
+	}
+}
+
+func TestWeeks(t *testing.T) {
+	checks := []*check{
+		// week 1 of 2025 starts on Monday December 30, 2024, and week 1 of 2026 on Monday December 29, 2025
+		newCheck(t, "weeks(1) dow(mon)", "2025-06-01T00:00:00Z", "2024-12-30T00:00:00Z", "2025-12-29T00:00:00Z"),
+		// 2025 has 52 weeks, and 2026 has 53, the last of which ends in 2027
+		newCheck(t, "weeks(-1) dow(fri)", "2026-06-01T00:00:00Z", "2025-12-26T00:00:00Z", "2027-01-01T00:00:00Z"),
+		newCheck(t, "weeks(*%2) dow(mon)", "2025-01-08T00:00:00Z", "2024-12-30T00:00:00Z", "2025-01-13T00:00:00Z"),
+		newCheck(t, "weeks(52..2, !1) dow(wed)", "2025-01-02T00:00:00Z", "2024-12-25T00:00:00Z", "2025-01-08T00:00:00Z"),
+		newParseErrorCheck("weeks(0)", 6),
+		newParseErrorCheck("weeks(54)", 6),
+		newParseErrorCheck("weeks(-54)", 6),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	checks := []*check{
+		// the count continues across the end of the year
+		newCheck(t, "every(10d, from 2025/3/1)", "2025-12-31T12:00:00Z", "2025-12-26T00:00:00Z", "2026-01-05T00:00:00Z"),
+		newCheck(t, "every(2w, from 2025/1/6) h(9)", "2025-01-10T00:00:00Z", "2025-01-06T09:00:00Z", "2025-01-20T09:00:00Z"),
+		newCheck(t, "EVERY(3 days, FROM 2024/2/27) h(12)", "2024-03-03T00:00:00Z", "2024-03-01T12:00:00Z", "2024-03-04T12:00:00Z"),
+		newParseErrorCheck("every(10d)", 0),
+		newParseErrorCheck("every(from 2025/3/1, 10d)", 0),
+		newParseErrorCheck("every(10h, from 2025/3/1)", 6),
+		newParseErrorCheck("every(400d, from 2025/3/1)", 6),
+		newParseErrorCheck("every(10d, from 3/1)", 16),
+		newParseErrorCheck("every(10d, !from 2025/3/1)", 11),
+		newParseErrorCheck("h(10d)", 4),
+	}
+
+	for _, c := range checks {
+		runTest(t, c)
+	}
+
+	// dates before the from date don't match
+	sch, _ := New("every(1d, from 2025/3/1)")
+	if prev, err := sch.PreviousAtOrBefore(parseTestTime(t, "2025-02-28T23:59:59Z")); err == nil {
+		t.Errorf("Expected no event before the from date, got %s", prev)
+	}
+
+	if text := Every(14, Date{2025, time.January, 6}).String(); text != "every(2w, from 2025/1/6)" {
+		t.Errorf("Unexpected builder text %q", text)
+	}
+
+	if formatted, err := Format("EVERY(14 days, FROM 2025/1/6)"); err != nil || formatted != "every(14d, from 2025/1/6)" {
+		t.Errorf("Unexpected format %q (%v)", formatted, err)
+	}
+}
+
+func TestNextAtOrAfterAndPreviousBefore(t *testing.T) {
+	tests := []struct {
+		format, date, next, prev string
+	}{
+		{"h(9)", "2025-06-15T09:00:00Z", "2025-06-15T09:00:00Z", "2025-06-14T09:00:00Z"},
+		{"h(9)", "2025-06-15T09:00:00.000000001Z", "2025-06-16T09:00:00Z", "2025-06-15T09:00:00Z"},
+		{"ms(0, 500)", "2025-06-15T10:00:00.5Z", "2025-06-15T10:00:00.5Z", "2025-06-15T10:00:00Z"},
+		{"ms(0, 500)", "2025-06-15T10:00:00.4999Z", "2025-06-15T10:00:00.5Z", "2025-06-15T10:00:00Z"},
+	}
+
+	for _, test := range tests {
+		sch, err := New(test.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		date := parseTestTime(t, test.date)
+		if next, err := sch.NextAtOrAfter(date); err != nil || !next.Equal(parseTestTime(t, test.next)) {
+			t.Errorf("%q NextAtOrAfter %s: expected %s, got %s (%v)", test.format, test.date, test.next, next, err)
+		}
+
+		if prev, err := sch.PreviousBefore(date); err != nil || !prev.Equal(parseTestTime(t, test.prev)) {
+			t.Errorf("%q PreviousBefore %s: expected %s, got %s (%v)", test.format, test.date, test.prev, prev, err)
+		}
+	}
+}
+
+func TestSearchHorizon(t *testing.T) {
+	date := parseTestTime(t, "2025-06-15T00:00:00Z")
+
+	// February 29th falls on a Monday in 2016 and 2044
+	sch, err := New("dates(2/29) dow(mon)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sch.NextAfter(date)
+	if notFound, ok := err.(*ValidTimeNotFoundError); !ok {
+		t.Errorf("Expected a ValidTimeNotFoundError, got %v", err)
+	} else if notFound.Horizon() != DefaultSearchHorizon || notFound.Direction() != SearchForward {
+		t.Errorf("Expected horizon %d searching forward, got %d searching %s", DefaultSearchHorizon, notFound.Horizon(), notFound.Direction())
+	}
+
+	sch, err = New("dates(2/29) dow(mon)", SearchHorizon(20*365))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next, err := sch.NextAfter(date); err != nil {
+		t.Error(err)
+	} else if !next.Equal(parseTestTime(t, "2044-02-29T00:00:00Z")) {
+		t.Errorf("Expected 2044-02-29, got %s", next)
+	}
+
+	if prev, err := sch.PreviousAtOrBefore(date); err != nil {
+		t.Error(err)
+	} else if !prev.Equal(parseTestTime(t, "2016-02-29T00:00:00Z")) {
+		t.Errorf("Expected 2016-02-29, got %s", prev)
+	}
+
+	sch, err = New("dates(2/29) dow(mon)", SearchHorizon(5*365))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sch.PreviousAtOrBefore(date)
+	if notFound, ok := err.(*ValidTimeNotFoundError); !ok {
+		t.Errorf("Expected a ValidTimeNotFoundError, got %v", err)
+	} else if notFound.Horizon() != 5*365 || notFound.Direction() != SearchBackward {
+		t.Errorf("Expected horizon %d searching backward, got %d searching %s", 5*365, notFound.Horizon(), notFound.Direction())
+	}
+}
+
+func TestMasksMatchRanges(t *testing.T) {
+	formats := []string{
+		"s(*%7, !14..20) min(!0..29) h(22..3)",
+		"s(50..10%3) min(*%5, 7) h(!*%2)",
+		"min(5..<10, 30..<31) h(9..<17)",
+		"dow(fri..mon, !sun, tue#2) months(nov..feb%2, !dec)",
+	}
+
+	inRanges := func(ranges []*internals.IrIntegerRange, value, lengthOfUnit int) bool {
+		for _, r := range ranges {
+			if r.Contains(value, lengthOfUnit) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	check := func(format, unit string, mask uint64, ranges, excluded []*internals.IrIntegerRange, min, max int) {
+		for value := min; value <= max; value++ {
+			expected := (len(ranges) == 0 || inRanges(ranges, value, max-min+1)) && !inRanges(excluded, value, max-min+1)
+			if actual := mask&(1<<uint(value)) != 0; actual != expected {
+				t.Errorf("%s: %s mask bit %d is %v, expected %v", format, unit, value, actual, expected)
+			}
+		}
+	}
+
+	for _, format := range formats {
+		sch, err := New(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		group := sch.(*scheduleImpl).ir.Groups[0]
+		check(format, "seconds", group.SecondsMask, group.Seconds, group.SecondsExcluded, 0, 59)
+		check(format, "minutes", group.MinutesMask, group.Minutes, group.MinutesExcluded, 0, 59)
+		check(format, "hours", uint64(group.HoursMask), group.Hours, group.HoursExcluded, 0, 23)
+		check(format, "months", uint64(group.MonthsMask), group.Months, group.MonthsExcluded, 1, 12)
+	}
+}
+
+func TestReportAllErrors(t *testing.T) {
+	tests := []struct {
+		format  string
+		indexes []int
+	}{
+		{"min(60) h(25) dow(8)", []int{4, 10, 18}},
+		{"min(5x, 6) foo(1) h(3", []int{5, 11, 21}},
+		{"{min(1..) h(2)} {dom(0)}", []int{8, 21}},
+		{"dow(jan, mon#9) months(13)", []int{4, 13, 23}},
+		{"{min(1, } {h(1)}, {h(2)", []int{8, 23}},
+		{"min(60)", []int{4}},
+		{"foo", []int{0}},
+	}
+
+	for _, test := range tests {
+		_, err := New(test.format, ReportAllErrors())
+		list, ok := err.(*internals.ParseErrorList)
+		if !ok {
+			t.Errorf("%q: expected a *ParseErrorList, got %v", test.format, err)
+			continue
+		}
+
+		var indexes []int
+		for _, e := range list.Errors() {
+			indexes = append(indexes, e.Index())
+		}
+
+		if fmt.Sprint(indexes) != fmt.Sprint(test.indexes) {
+			t.Errorf("%q. Expected errors at %v, got %v:\n%s", test.format, test.indexes, indexes, err)
+		}
+
+		if list.Index() != test.indexes[0] {
+			t.Errorf("%q. Expected Index() %d, got %d", test.format, test.indexes[0], list.Index())
+		}
+
+		// without the option, only the first error is reported
+		_, err = New(test.format)
+		if parseError, ok := err.(*internals.ParseError); !ok || parseError.Index() != test.indexes[0] {
+			t.Errorf("%q. Expected a single error at %d, got %v", test.format, test.indexes[0], err)
+		}
+	}
+
+	if _, err := New("min(0) {h(1)}", ReportAllErrors()); err != nil {
+		t.Error(err)
+	}
+}
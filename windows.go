@@ -0,0 +1,257 @@
+package schyntax
+
+import (
+	"iter"
+	"time"
+
+	"github.com/schyntax/go-schyntax/internals"
+)
+
+// Window is an interval of time during which a schedule matches. Start is inclusive, and End is exclusive.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Windows is a view of a schedule as intervals of time, rather than instants. Each event of a group covers one unit of
+// the smallest unit which the group's expressions specify, so "h(2..<4)" covers 02:00 to 04:00 rather than firing at
+// 02:00 and 03:00, and "dow(sat, sun)" covers whole days. A unit which every value of matches is covered by the next
+// larger unit, so "min(*) h(9)" covers 09:00 to 10:00. Windows which touch or overlap, including those of different
+// groups, are coalesced into one.
+//
+// Searches are limited by the schedule's horizon, so a window which continues for longer than that many days from the
+// time it's found from is cut off at the horizon.
+type Windows struct {
+	s          *scheduleImpl
+	groups     []*internals.IrGroup // the schedule's groups, with the units below each group's window unit set to zero
+	units      []windowUnit
+	maxGranule time.Duration // the longest that a single event of any group can cover
+}
+
+type windowUnit int8
+
+const (
+	windowMillisecond windowUnit = iota
+	windowSecond
+	windowMinute
+	windowHour
+	windowDay
+)
+
+// the length of each window unit. Days are covered until the following midnight, so they can be longer or shorter than
+// 24 hours across a daylight saving transition, and the length here is the longest a day can be.
+var windowUnitDurations = [...]time.Duration{time.Millisecond, time.Second, time.Minute, time.Hour, 25 * time.Hour}
+
+var allMilliseconds = func() internals.MillisecondsMask {
+	var mask internals.MillisecondsMask
+	for ms := 0; ms <= 999; ms++ {
+		mask.Set(ms)
+	}
+
+	return mask
+}()
+
+func (s *scheduleImpl) Windows() *Windows {
+	w := &Windows{s: s}
+	for _, group := range s.ir.Groups {
+		g, unit := windowGroup(group)
+		w.groups = append(w.groups, g)
+		w.units = append(w.units, unit)
+		w.maxGranule = max(w.maxGranule, windowUnitDurations[unit])
+	}
+
+	return w
+}
+
+// windowGroup returns a copy of the group whose events are at the start of each unit it covers, and that unit.
+func windowGroup(group *internals.IrGroup) (*internals.IrGroup, windowUnit) {
+	var unit windowUnit
+	switch group.SmallestUnit {
+	case internals.ExpressionTypeMilliseconds:
+		unit = windowMillisecond
+	case internals.ExpressionTypeSeconds:
+		unit = windowSecond
+	case internals.ExpressionTypeMinutes:
+		unit = windowMinute
+	case internals.ExpressionTypeHours:
+		unit = windowHour
+	default:
+		unit = windowDay
+	}
+
+	// when every value of a unit matches, each match of the unit above it is covered in full
+	if unit == windowMillisecond && group.MillisecondsMask == allMilliseconds {
+		unit = windowSecond
+	}
+
+	if unit == windowSecond && group.SecondsMask == 1<<60-1 {
+		unit = windowMinute
+	}
+
+	if unit == windowMinute && group.MinutesMask == 1<<60-1 {
+		unit = windowHour
+	}
+
+	if unit == windowHour && group.HoursMask == 1<<24-1 {
+		unit = windowDay
+	}
+
+	g := *group
+	if unit > windowMillisecond {
+		g.MillisecondsMask = internals.ZeroMilliseconds
+	}
+
+	if unit > windowSecond {
+		g.SecondsMask = 1
+	}
+
+	if unit > windowMinute {
+		g.MinutesMask = 1
+	}
+
+	if unit > windowHour {
+		g.HoursMask = 1
+	}
+
+	return &g, unit
+}
+
+// Contains returns true if t is within one of the schedule's windows.
+func (w *Windows) Contains(t time.Time) bool {
+	for iv := range w.intervals(t, searchModeAtOrBefore) {
+		if iv.Start.Add(w.maxGranule).Before(t) {
+			break
+		}
+
+		if iv.End.After(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CurrentWindow returns the window which contains t, and false if t isn't within a window.
+func (w *Windows) CurrentWindow(t time.Time) (Window, bool) {
+	var window Window
+	found := false
+	limit := t.AddDate(0, 0, -w.s.horizon)
+	for iv := range w.intervals(t, searchModeAtOrBefore) {
+		// events are visited by their starts, so once one starts far enough back, no earlier event can reach the window
+		edge := t
+		if found {
+			edge = window.Start
+		}
+
+		if iv.Start.Add(w.maxGranule).Before(edge) || iv.Start.Before(limit) {
+			break
+		}
+
+		if !found {
+			if iv.End.After(t) {
+				window = iv
+				found = true
+			}
+		} else if !iv.End.Before(window.Start) {
+			window.Start = minTime(window.Start, iv.Start)
+			window.End = maxTime(window.End, iv.End)
+		}
+	}
+
+	if !found {
+		return Window{}, false
+	}
+
+	return w.extend(window, t), true
+}
+
+// NextWindow returns the first window which starts after t, not counting the window which contains t. If there isn't
+// one within the schedule's search horizon, a *ValidTimeNotFoundError is returned.
+func (w *Windows) NextWindow(t time.Time) (Window, error) {
+	after := t
+	if current, ok := w.CurrentWindow(t); ok {
+		after = current.End
+	}
+
+	for iv := range w.intervals(after, searchModeAfter) {
+		return w.extend(iv, iv.Start), nil
+	}
+
+	return Window{}, &ValidTimeNotFoundError{w.s.originalText, w.s.horizon, SearchForward}
+}
+
+// extend returns the window, extended by every event at or after from which starts before the window ends.
+func (w *Windows) extend(window Window, from time.Time) Window {
+	limit := from.AddDate(0, 0, w.s.horizon)
+	for iv := range w.intervals(from.Add(-time.Nanosecond), searchModeAfter) {
+		if iv.Start.After(window.End) || iv.Start.After(limit) {
+			break
+		}
+
+		window.End = maxTime(window.End, iv.End)
+	}
+
+	return window
+}
+
+// intervals returns an iterator over the interval covered by each event of every group, ordered by their starts in
+// the direction of the search mode.
+func (w *Windows) intervals(start time.Time, mode searchMode) iter.Seq[Window] {
+	return func(yield func(Window) bool) {
+		start := start.In(w.s.loc)
+
+		sequences := make([]iter.Seq[Window], len(w.groups))
+		for i, group := range w.groups {
+			sequences[i] = w.groupIntervals(group, w.units[i], start, mode)
+		}
+
+		h := newCursorHeap(sequences, func(a, b Window) bool {
+			return mode.isNearer(a.Start, b.Start)
+		})
+		defer h.stop()
+
+		for h.Len() > 0 {
+			if !yield(h.cursors[0].event) {
+				return
+			}
+
+			h.advance()
+		}
+	}
+}
+
+func (w *Windows) groupIntervals(group *internals.IrGroup, unit windowUnit, start time.Time, mode searchMode) iter.Seq[Window] {
+	return func(yield func(Window) bool) {
+		offset := w.s.jitterOffset(group)
+		for e := range w.s.groupEvents(group, start, mode) {
+			var end time.Time
+			if unit == windowDay {
+				// a day is covered until the following midnight, which is found without the jitter
+				wall := wallClock(e.Add(-offset).In(w.s.loc))
+				end = localTime(wall.Year(), int(wall.Month()), wall.Day()+1, 0, 0, 0, w.s.loc).Add(offset)
+			} else {
+				end = e.Add(windowUnitDurations[unit])
+			}
+
+			if !yield(Window{e, end}) {
+				return
+			}
+		}
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,72 @@
+package schyntax
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWindows(t *testing.T) {
+	// June 2, 2025 is a Monday
+	tests := []struct {
+		format        string
+		date          string
+		current, next [2]string // the zero value means there is no current window
+	}{
+		{"h(2..<4)", "2025-06-02T03:30:00Z", [2]string{"2025-06-02T02:00:00Z", "2025-06-02T04:00:00Z"}, [2]string{"2025-06-03T02:00:00Z", "2025-06-03T04:00:00Z"}},
+		{"h(2..<4)", "2025-06-02T02:00:00Z", [2]string{"2025-06-02T02:00:00Z", "2025-06-02T04:00:00Z"}, [2]string{"2025-06-03T02:00:00Z", "2025-06-03T04:00:00Z"}},
+		{"h(2..<4)", "2025-06-02T04:00:00Z", [2]string{}, [2]string{"2025-06-03T02:00:00Z", "2025-06-03T04:00:00Z"}},
+		{"h(23..1)", "2025-06-03T00:30:00Z", [2]string{"2025-06-02T23:00:00Z", "2025-06-03T02:00:00Z"}, [2]string{"2025-06-03T23:00:00Z", "2025-06-04T02:00:00Z"}},
+		{"dow(mon..fri)", "2025-06-04T12:00:00Z", [2]string{"2025-06-02T00:00:00Z", "2025-06-07T00:00:00Z"}, [2]string{"2025-06-09T00:00:00Z", "2025-06-14T00:00:00Z"}},
+		{"min(0) h(9)", "2025-06-02T09:00:30Z", [2]string{"2025-06-02T09:00:00Z", "2025-06-02T09:01:00Z"}, [2]string{"2025-06-03T09:00:00Z", "2025-06-03T09:01:00Z"}},
+		{"min(*) h(9)", "2025-06-02T09:30:00Z", [2]string{"2025-06-02T09:00:00Z", "2025-06-02T10:00:00Z"}, [2]string{"2025-06-03T09:00:00Z", "2025-06-03T10:00:00Z"}},
+		{"s(*%2)", "2025-06-02T09:30:00.5Z", [2]string{"2025-06-02T09:30:00Z", "2025-06-02T09:30:01Z"}, [2]string{"2025-06-02T09:30:02Z", "2025-06-02T09:30:03Z"}},
+		{"{h(9)}, {h(10) min(*)}", "2025-06-02T10:15:00Z", [2]string{"2025-06-02T09:00:00Z", "2025-06-02T11:00:00Z"}, [2]string{"2025-06-03T09:00:00Z", "2025-06-03T11:00:00Z"}},
+		{"{h(9..11) dow(mon)}, {h(10..12) dow(mon)}", "2025-06-02T08:00:00Z", [2]string{}, [2]string{"2025-06-02T09:00:00Z", "2025-06-02T13:00:00Z"}},
+	}
+
+	for _, test := range tests {
+		sch, err := New(test.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := sch.Windows()
+		date := parseTestTime(t, test.date)
+
+		current, ok := w.CurrentWindow(date)
+		if ok != (test.current[0] != "") {
+			t.Errorf("%s at %s: expected a current window: %t", test.format, test.date, !ok)
+		} else if ok {
+			assertWindow(t, test.format+" current", current, test.current)
+		}
+
+		if w.Contains(date) != ok {
+			t.Errorf("%s at %s: expected Contains to be %t", test.format, test.date, ok)
+		}
+
+		next, err := w.NextWindow(date)
+		if err != nil {
+			t.Errorf("%s at %s: %v", test.format, test.date, err)
+		} else {
+			assertWindow(t, test.format+" next", next, test.next)
+		}
+	}
+
+	sch, err := New("dates(2024/1/1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sch.Windows().NextWindow(parseTestTime(t, "2025-06-02T00:00:00Z"))
+	var notFound *ValidTimeNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Expected a *ValidTimeNotFoundError, got %v", err)
+	}
+}
+
+func assertWindow(t *testing.T, name string, actual Window, expected [2]string) {
+	t.Helper()
+	if !actual.Start.Equal(parseTestTime(t, expected[0])) || !actual.End.Equal(parseTestTime(t, expected[1])) {
+		t.Errorf("%s. Expected: [%s, %s), Actual: [%s, %s)", name, expected[0], expected[1], actual.Start, actual.End)
+	}
+}